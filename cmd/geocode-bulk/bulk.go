@@ -0,0 +1,612 @@
+/**
+ * go-mapbox Bulk Geocoding Tool
+ * Rate limited concurrent geocoding pipeline with checkpointing
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/geocode"
+)
+
+const (
+	modeForward = "forward"
+	modeReverse = "reverse"
+
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+
+	maxRetries = 3
+)
+
+// retryBackoff is the initial delay before retrying a rate limited request
+var retryBackoff = time.Second
+
+// contextLevels are the context levels output as columns, from most to least specific
+var contextLevels = []string{"address", "neighborhood", "locality", "place", "district", "postcode", "region", "country"}
+
+// config configures a bulk geocoding run
+type config struct {
+	Input           string
+	Output          string
+	Format          string
+	Checkpoint      string
+	Mode            string
+	Columns         []string
+	LatColumn       string
+	LngColumn       string
+	Country         string
+	Types           []geocode.Type
+	Permanent       bool
+	Concurrency     int
+	Rate            float64
+	CheckpointEvery int
+}
+
+func (c *config) validate() error {
+	if c.Input == "" || c.Output == "" {
+		return errors.New("input and output files are required")
+	}
+	if c.Format == "" {
+		c.Format = strings.TrimPrefix(filepath.Ext(c.Input), ".")
+	}
+	if c.Format != formatCSV && c.Format != formatJSONL {
+		return fmt.Errorf("unsupported format '%s' (expected csv or jsonl)", c.Format)
+	}
+	if c.Mode != modeForward && c.Mode != modeReverse {
+		return fmt.Errorf("unsupported mode '%s' (expected forward or reverse)", c.Mode)
+	}
+	if c.Mode == modeForward && len(c.Columns) == 0 {
+		return errors.New("forward geocoding requires query columns")
+	}
+	if c.Checkpoint == "" {
+		c.Checkpoint = c.Output + ".checkpoint"
+	}
+	if c.Concurrency < 1 {
+		c.Concurrency = 1
+	}
+	if c.Rate <= 0 {
+		return errors.New("rate must be greater than zero")
+	}
+	if c.CheckpointEvery < 1 {
+		c.CheckpointEvery = 1
+	}
+	return nil
+}
+
+// row is a single input record
+type row struct {
+	index  int
+	fields map[string]string
+	raw    map[string]interface{}
+}
+
+// match is the output for a successfully geocoded row
+type match struct {
+	Latitude  float64           `json:"lat"`
+	Longitude float64           `json:"lng"`
+	Relevance float64           `json:"relevance"`
+	PlaceType []string          `json:"place_type"`
+	PlaceName string            `json:"place_name"`
+	ID        string            `json:"id"`
+	Context   map[string]string `json:"context"`
+}
+
+// result is the output for a row
+type result struct {
+	row   row
+	Match *match `json:"match,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// checkpoint records progress through the input and the matching output length
+type checkpoint struct {
+	Rows   int   `json:"rows"`
+	Offset int64 `json:"offset"`
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &checkpoint{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := checkpoint{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s (%s)", path, err)
+	}
+
+	return &c, nil
+}
+
+func saveCheckpoint(path string, c *checkpoint) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// run executes a bulk geocoding job, returning the number of rows written
+func run(cfg *config, g *geocode.Geocode) (int, error) {
+	if err := cfg.validate(); err != nil {
+		return 0, err
+	}
+
+	cp, err := loadCheckpoint(cfg.Checkpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	in, err := os.Open(cfg.Input)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	var reader rowReader
+	if cfg.Format == formatCSV {
+		reader, err = newCSVReader(in)
+	} else {
+		reader = newJSONLReader(in)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if cfg.Format == formatCSV {
+		if err := checkColumns(cfg, reader.Header()); err != nil {
+			return 0, err
+		}
+	}
+
+	// Skip rows completed in a previous run
+	for i := 0; i < cp.Rows; i++ {
+		if _, _, err := reader.Read(); err != nil {
+			return 0, fmt.Errorf("checkpoint exceeds input length (%s)", err)
+		}
+	}
+
+	// Open output, discarding anything written after the last checkpoint
+	out, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	if err := out.Truncate(cp.Offset); err != nil {
+		return 0, err
+	}
+	if _, err := out.Seek(cp.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	counter := &countingWriter{w: out}
+	var writer rowWriter
+	if cfg.Format == formatCSV {
+		writer = newCSVWriter(counter, reader.Header())
+	} else {
+		writer = newJSONLWriter(counter)
+	}
+
+	if cp.Rows == 0 && cp.Offset == 0 {
+		if err := writer.WriteHeader(); err != nil {
+			return 0, err
+		}
+	}
+
+	// Start pipeline
+	jobs := make(chan row)
+	results := make(chan result)
+	stop := make(chan struct{})
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(jobs)
+		for i := cp.Rows; ; i++ {
+			fields, raw, err := reader.Read()
+			if err == io.EOF {
+				readErr <- nil
+				return
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case jobs <- row{i, fields, raw}:
+			case <-stop:
+				readErr <- nil
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+	defer ticker.Stop()
+
+	done := make(chan struct{})
+	for i := 0; i < cfg.Concurrency; i++ {
+		go func() {
+			for r := range jobs {
+				results <- geocodeRow(cfg, g, ticker.C, r)
+			}
+			done <- struct{}{}
+		}()
+	}
+	go func() {
+		for i := 0; i < cfg.Concurrency; i++ {
+			<-done
+		}
+		close(results)
+	}()
+
+	// Write results in input order, checkpointing as we go
+	pending := make(map[int]result)
+	next, written := cp.Rows, 0
+	var writeErr error
+
+	commit := func() error {
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		return saveCheckpoint(cfg.Checkpoint, &checkpoint{Rows: next, Offset: cp.Offset + counter.n})
+	}
+
+	for res := range results {
+		if writeErr != nil {
+			continue
+		}
+		pending[res.row.index] = res
+
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			if writeErr = writer.Write(&r); writeErr != nil {
+				close(stop)
+				break
+			}
+			next++
+			written++
+
+			if written%cfg.CheckpointEvery == 0 {
+				if writeErr = commit(); writeErr != nil {
+					close(stop)
+					break
+				}
+			}
+		}
+	}
+
+	if writeErr != nil {
+		return written, writeErr
+	}
+	if err := <-readErr; err != nil {
+		commit()
+		return written, err
+	}
+
+	return written, commit()
+}
+
+func checkColumns(cfg *config, header []string) error {
+	columns := cfg.Columns
+	if cfg.Mode == modeReverse {
+		columns = []string{cfg.LatColumn, cfg.LngColumn}
+	}
+
+	for _, c := range columns {
+		found := false
+		for _, h := range header {
+			if h == c {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("column '%s' not found in input", c)
+		}
+	}
+
+	return nil
+}
+
+// geocodeRow geocodes a single row, retrying rate limited requests
+func geocodeRow(cfg *config, g *geocode.Geocode, limit <-chan time.Time, r row) result {
+	res := result{row: r}
+
+	var err error
+	var features *base.FeatureCollection
+	backoff := retryBackoff
+
+	for attempt := 0; ; attempt++ {
+		<-limit
+
+		features, err = lookup(cfg, g, r)
+		if err != base.ErrorAPILimitExceeded || attempt == maxRetries {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if features == nil || len(features.Features) == 0 {
+		res.Error = "no match"
+		return res
+	}
+
+	f := features.Features[0]
+	m := match{
+		Relevance: f.Relevance,
+		PlaceType: f.PlaceType,
+		PlaceName: f.PlaceName,
+		ID:        f.ID,
+		Context:   make(map[string]string),
+	}
+	if len(f.Center) == 2 {
+		m.Longitude, m.Latitude = f.Center[0], f.Center[1]
+	}
+	for _, c := range f.Context {
+		m.Context[contextLevel(c.ID)] = c.Text
+	}
+	// Include the matched feature itself at its own level
+	if len(f.PlaceType) > 0 {
+		m.Context[f.PlaceType[0]] = f.Text
+	}
+
+	res.Match = &m
+
+	return res
+}
+
+func lookup(cfg *config, g *geocode.Geocode, r row) (*base.FeatureCollection, error) {
+	if cfg.Mode == modeReverse {
+		lat, err := strconv.ParseFloat(strings.TrimSpace(r.fields[cfg.LatColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude '%s'", r.fields[cfg.LatColumn])
+		}
+		lng, err := strconv.ParseFloat(strings.TrimSpace(r.fields[cfg.LngColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude '%s'", r.fields[cfg.LngColumn])
+		}
+
+		opts := geocode.ReverseRequestOpts{Types: cfg.Types, Limit: 1}
+		resp, err := g.Reverse(&base.Location{Latitude: lat, Longitude: lng}, &opts)
+		if err != nil {
+			return nil, err
+		}
+		return resp.FeatureCollection, nil
+	}
+
+	parts := make([]string, 0, len(cfg.Columns))
+	for _, c := range cfg.Columns {
+		if v := strings.TrimSpace(r.fields[c]); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("empty query")
+	}
+
+	opts := geocode.ForwardRequestOpts{Country: cfg.Country, Types: cfg.Types, Limit: 1}
+	resp, err := g.Forward(strings.Join(parts, ", "), &opts, cfg.Permanent)
+	if err != nil {
+		return nil, err
+	}
+	return resp.FeatureCollection, nil
+}
+
+// contextLevel extracts the level from a context ID (eg. place.123 -> place)
+func contextLevel(id string) string {
+	if i := strings.Index(id, "."); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// rowReader reads input rows
+type rowReader interface {
+	Header() []string
+	Read() (map[string]string, map[string]interface{}, error)
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	c := &csvReader{r: csv.NewReader(r)}
+	c.r.FieldsPerRecord = -1
+
+	header, err := c.r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header (%s)", err)
+	}
+	c.header = header
+
+	return c, nil
+}
+
+func (c *csvReader) Header() []string {
+	return c.header
+}
+
+func (c *csvReader) Read() (map[string]string, map[string]interface{}, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]string, len(c.header))
+	for i, h := range c.header {
+		if i < len(record) {
+			fields[h] = record[i]
+		}
+	}
+
+	return fields, nil, nil
+}
+
+type jsonlReader struct {
+	d *json.Decoder
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	return &jsonlReader{d}
+}
+
+func (j *jsonlReader) Header() []string {
+	return nil
+}
+
+func (j *jsonlReader) Read() (map[string]string, map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+	if err := j.d.Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if v != nil {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+
+	return fields, raw, nil
+}
+
+// rowWriter writes output rows
+type rowWriter interface {
+	WriteHeader() error
+	Write(r *result) error
+	Flush() error
+}
+
+// countingWriter counts bytes written to the underlying writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header []string
+}
+
+func newCSVWriter(w io.Writer, header []string) *csvWriter {
+	return &csvWriter{csv.NewWriter(w), header}
+}
+
+func (c *csvWriter) WriteHeader() error {
+	header := append([]string{}, c.header...)
+	header = append(header, "lat", "lng", "relevance", "place_type", "place_name", "id")
+	for _, l := range contextLevels {
+		header = append(header, "context_"+l)
+	}
+	header = append(header, "error")
+
+	return c.w.Write(header)
+}
+
+func (c *csvWriter) Write(r *result) error {
+	record := make([]string, 0, len(c.header)+len(contextLevels)+7)
+	for _, h := range c.header {
+		record = append(record, r.row.fields[h])
+	}
+
+	if m := r.Match; m != nil {
+		record = append(record,
+			strconv.FormatFloat(m.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(m.Longitude, 'f', -1, 64),
+			strconv.FormatFloat(m.Relevance, 'f', -1, 64),
+			strings.Join(m.PlaceType, ","),
+			m.PlaceName,
+			m.ID)
+		for _, l := range contextLevels {
+			record = append(record, m.Context[l])
+		}
+	} else {
+		for i := 0; i < len(contextLevels)+6; i++ {
+			record = append(record, "")
+		}
+	}
+	record = append(record, r.Error)
+
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	w *bufio.Writer
+	e *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	b := bufio.NewWriter(w)
+	return &jsonlWriter{b, json.NewEncoder(b)}
+}
+
+func (j *jsonlWriter) WriteHeader() error {
+	return nil
+}
+
+// Write writes the input record with results added under the "geocode" key
+func (j *jsonlWriter) Write(r *result) error {
+	out := make(map[string]interface{}, len(r.row.raw)+1)
+	for k, v := range r.row.raw {
+		out[k] = v
+	}
+	out["geocode"] = r
+
+	return j.e.Encode(out)
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.w.Flush()
+}
@@ -0,0 +1,196 @@
+/**
+ * go-mapbox Bulk Geocoding Tool Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/geocode"
+)
+
+func TestBulk(t *testing.T) {
+
+	var mu sync.Mutex
+	queries := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.EscapedPath()
+		q, _ := url.PathUnescape(strings.TrimSuffix(path[strings.LastIndex(path, "/")+1:], ".json"))
+
+		mu.Lock()
+		queries = append(queries, q)
+		mu.Unlock()
+
+		if strings.Contains(q, "nowhere") {
+			json.NewEncoder(w).Encode(&base.FeatureCollection{Type: "FeatureCollection"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(&base.FeatureCollection{
+			Type: "FeatureCollection",
+			Features: []base.Feature{{
+				ID:        "address.1",
+				Text:      "Main Street",
+				PlaceName: fmt.Sprintf("Match for %s", q),
+				PlaceType: []string{"address"},
+				Relevance: 0.9,
+				Center:    base.Point{174.76, -36.85},
+				Context: []base.Context{
+					{ID: "place.2", Text: "Auckland"},
+					{ID: "country.3", Text: "New Zealand"},
+				},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("fake-token")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	b.SetBaseURL(server.URL)
+	g := geocode.NewGeocode(b)
+
+	dir, err := ioutil.TempDir("", "go-mapbox-bulk")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	readCSV := func(path string) [][]string {
+		f, err := os.Open(path)
+		assert.Nil(t, err)
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		assert.Nil(t, err)
+		return records
+	}
+
+	t.Run("Geocodes csv in input order", func(t *testing.T) {
+		input := filepath.Join(dir, "in.csv")
+		output := filepath.Join(dir, "out.csv")
+		ioutil.WriteFile(input, []byte("id,street,city\n1,1 Main St,Auckland\n2,,nowhere\n3,3 Main St,Auckland\n4,Unit 3/12 #5 Main St?,Auckland\n"), 0666)
+
+		n, err := run(&config{
+			Input: input, Output: output, Mode: modeForward, Columns: []string{"street", "city"},
+			Concurrency: 3, Rate: 1000,
+		}, g)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 4, n)
+
+		records := readCSV(output)
+		if !assert.Len(t, records, 5) {
+			t.FailNow()
+		}
+
+		header := records[0]
+		col := func(name string) int {
+			for i, h := range header {
+				if h == name {
+					return i
+				}
+			}
+			t.Fatalf("missing column %s", name)
+			return -1
+		}
+
+		assert.EqualValues(t, "1", records[1][col("id")])
+		assert.EqualValues(t, "-36.85", records[1][col("lat")])
+		assert.EqualValues(t, "174.76", records[1][col("lng")])
+		assert.EqualValues(t, "0.9", records[1][col("relevance")])
+		assert.EqualValues(t, "Match for 1 Main St, Auckland", records[1][col("place_name")])
+		assert.EqualValues(t, "Auckland", records[1][col("context_place")])
+		assert.EqualValues(t, "New Zealand", records[1][col("context_country")])
+		assert.EqualValues(t, "Main Street", records[1][col("context_address")])
+
+		assert.EqualValues(t, "2", records[2][col("id")])
+		assert.EqualValues(t, "no match", records[2][col("error")])
+
+		assert.EqualValues(t, "3", records[3][col("id")])
+
+		assert.EqualValues(t, "4", records[4][col("id")])
+		assert.EqualValues(t, "Match for Unit 3/12 #5 Main St?, Auckland", records[4][col("place_name")])
+	})
+
+	t.Run("Resumes from checkpoints", func(t *testing.T) {
+		input := filepath.Join(dir, "resume.csv")
+		output := filepath.Join(dir, "resume-out.csv")
+
+		// Complete the first two rows
+		ioutil.WriteFile(input, []byte("lat,lng\n1,2\n3,4\n"), 0666)
+		cfg := config{Input: input, Output: output, Mode: modeReverse, LatColumn: "lat", LngColumn: "lng", Rate: 1000}
+		n, err := run(&cfg, g)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, n)
+
+		// Simulate a partial write after the last checkpoint
+		f, _ := os.OpenFile(output, os.O_APPEND|os.O_WRONLY, 0666)
+		f.WriteString("partial,row")
+		f.Close()
+
+		mu.Lock()
+		queries = queries[:0]
+		mu.Unlock()
+
+		ioutil.WriteFile(input, []byte("lat,lng\n1,2\n3,4\n5,6\n"), 0666)
+		n, err = run(&cfg, g)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, n)
+
+		assert.EqualValues(t, []string{"6.000000,5.000000"}, queries)
+
+		records := readCSV(output)
+		if assert.Len(t, records, 4) {
+			assert.EqualValues(t, "lat", records[0][0])
+			assert.EqualValues(t, []string{"1", "3", "5"}, []string{records[1][0], records[2][0], records[3][0]})
+		}
+	})
+
+	t.Run("Geocodes jsonl", func(t *testing.T) {
+		input := filepath.Join(dir, "in.jsonl")
+		output := filepath.Join(dir, "out.jsonl")
+		ioutil.WriteFile(input, []byte("{\"id\":1,\"address\":\"1 Main St\"}\n{\"id\":2,\"address\":\"2 Main St\"}\n"), 0666)
+
+		n, err := run(&config{Input: input, Output: output, Mode: modeForward, Columns: []string{"address"}, Rate: 1000}, g)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, n)
+
+		data, err := ioutil.ReadFile(output)
+		assert.Nil(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if assert.Len(t, lines, 2) {
+			out := struct {
+				ID      int
+				Geocode struct {
+					Match match
+				}
+			}{}
+			assert.Nil(t, json.Unmarshal([]byte(lines[1]), &out))
+			assert.EqualValues(t, 2, out.ID)
+			assert.EqualValues(t, -36.85, out.Geocode.Match.Latitude)
+			assert.EqualValues(t, "Auckland", out.Geocode.Match.Context["place"])
+		}
+	})
+}
@@ -0,0 +1,67 @@
+/**
+ * go-mapbox Bulk Geocoding Tool
+ * Forward or reverse geocodes CSV or JSONL files using the mapbox geocoding API
+ * See https://www.mapbox.com/api-documentation/#geocoding for API information
+ *
+ * Usage: MAPBOX_TOKEN=... geocode-bulk -input addresses.csv -output results.csv -columns street,city,zip
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/geocode"
+)
+
+func main() {
+	var cfg config
+	var columns, types, token string
+
+	flag.StringVar(&cfg.Input, "input", "", "input file (.csv or .jsonl)")
+	flag.StringVar(&cfg.Output, "output", "", "output file (.csv or .jsonl)")
+	flag.StringVar(&cfg.Format, "format", "", "input and output format (csv or jsonl), defaults to the input file extension")
+	flag.StringVar(&cfg.Checkpoint, "checkpoint", "", "checkpoint file for resuming, defaults to <output>.checkpoint")
+	flag.StringVar(&cfg.Mode, "mode", modeForward, "geocoding mode (forward or reverse)")
+	flag.StringVar(&columns, "columns", "", "comma separated input columns joined to form forward queries")
+	flag.StringVar(&cfg.LatColumn, "lat-column", "lat", "input latitude column for reverse geocoding")
+	flag.StringVar(&cfg.LngColumn, "lng-column", "lng", "input longitude column for reverse geocoding")
+	flag.StringVar(&cfg.Country, "country", "", "comma separated ISO 3166 country codes to limit forward results")
+	flag.StringVar(&types, "types", "", "comma separated place types to limit results")
+	flag.BoolVar(&cfg.Permanent, "permanent", false, "use the permanent geocoding endpoint (required to store results)")
+	flag.IntVar(&cfg.Concurrency, "concurrency", 4, "number of concurrent requests")
+	flag.Float64Var(&cfg.Rate, "rate", 10, "maximum requests per second")
+	flag.IntVar(&cfg.CheckpointEvery, "checkpoint-every", 100, "rows written between checkpoints")
+	flag.StringVar(&token, "token", os.Getenv("MAPBOX_TOKEN"), "mapbox API token, defaults to $MAPBOX_TOKEN")
+	flag.Parse()
+
+	if columns != "" {
+		cfg.Columns = strings.Split(columns, ",")
+	}
+	if types != "" {
+		for _, t := range strings.Split(types, ",") {
+			cfg.Types = append(cfg.Types, geocode.Type(t))
+		}
+	}
+
+	b, err := base.NewBase(token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	n, err := run(&cfg, geocode.NewGeocode(b))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s (%d rows written)\n", err, n)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Geocoded %d rows\n", n)
+}
@@ -0,0 +1,343 @@
+/**
+ * go-mapbox Address Normalization
+ * Offline address normalization to improve match relevance and cache hit rates when geocoding
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package address
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// StreetTypes maps lower case street type abbreviations to their expansions
+var StreetTypes = map[string]string{
+	"aly":   "Alley",
+	"ave":   "Avenue",
+	"av":    "Avenue",
+	"blvd":  "Boulevard",
+	"cct":   "Circuit",
+	"cir":   "Circle",
+	"cl":    "Close",
+	"cres":  "Crescent",
+	"crt":   "Court",
+	"ct":    "Court",
+	"dr":    "Drive",
+	"esp":   "Esplanade",
+	"expy":  "Expressway",
+	"fwy":   "Freeway",
+	"gr":    "Grove",
+	"hwy":   "Highway",
+	"ln":    "Lane",
+	"pde":   "Parade",
+	"pkwy":  "Parkway",
+	"pl":    "Place",
+	"plz":   "Plaza",
+	"rd":    "Road",
+	"sq":    "Square",
+	"st":    "Street",
+	"ter":   "Terrace",
+	"terr":  "Terrace",
+	"tce":   "Terrace",
+	"trl":   "Trail",
+	"way":   "Way",
+	"hts":   "Heights",
+	"mtwy":  "Motorway",
+	"cswy":  "Causeway",
+	"bvd":   "Boulevard",
+	"crst":  "Crest",
+	"rdg":   "Ridge",
+	"sdrd":  "Sideroad",
+	"tpke":  "Turnpike",
+	"xing":  "Crossing",
+	"jct":   "Junction",
+	"lndg":  "Landing",
+	"mnr":   "Manor",
+	"pt":    "Point",
+	"rte":   "Route",
+	"vw":    "View",
+	"vlg":   "Village",
+	"grn":   "Green",
+	"gdns":  "Gardens",
+	"prom":  "Promenade",
+	"qy":    "Quay",
+	"rise":  "Rise",
+	"row":   "Row",
+	"wlk":   "Walk",
+	"wy":    "Way",
+	"cv":    "Cove",
+	"bnd":   "Bend",
+	"brg":   "Bridge",
+	"byp":   "Bypass",
+	"ctr":   "Centre",
+	"frwy":  "Freeway",
+	"hwys":  "Highways",
+	"pkway": "Parkway",
+}
+
+// Directionals maps lower case directional abbreviations to their expansions
+var Directionals = map[string]string{
+	"n":  "North",
+	"s":  "South",
+	"e":  "East",
+	"w":  "West",
+	"ne": "Northeast",
+	"nw": "Northwest",
+	"se": "Southeast",
+	"sw": "Southwest",
+}
+
+// UnitDesignators maps lower case unit designators to their canonical forms
+var UnitDesignators = map[string]string{
+	"apt":       "Apt",
+	"apartment": "Apt",
+	"unit":      "Unit",
+	"suite":     "Suite",
+	"ste":       "Suite",
+	"flat":      "Flat",
+	"floor":     "Floor",
+	"rm":        "Room",
+	"room":      "Room",
+	"lot":       "Lot",
+	"shop":      "Shop",
+	"#":         "#",
+}
+
+// StateCodes lists lower case state, territory and province codes that are upper cased in place names
+var StateCodes = map[string]bool{
+	// United States
+	"al": true, "ak": true, "az": true, "ar": true, "ca": true, "co": true, "ct": true, "de": true,
+	"dc": true, "fl": true, "ga": true, "hi": true, "id": true, "il": true, "in": true, "ia": true,
+	"ks": true, "ky": true, "la": true, "me": true, "md": true, "ma": true, "mi": true, "mn": true,
+	"ms": true, "mo": true, "mt": true, "ne": true, "nv": true, "nh": true, "nj": true, "nm": true,
+	"ny": true, "nc": true, "nd": true, "oh": true, "ok": true, "or": true, "pa": true, "ri": true,
+	"sc": true, "sd": true, "tn": true, "tx": true, "ut": true, "vt": true, "va": true, "wa": true,
+	"wv": true, "wi": true, "wy": true, "pr": true,
+	// Australia
+	"act": true, "nsw": true, "nt": true, "qld": true, "sa": true, "tas": true, "vic": true,
+	// Canada
+	"ab": true, "bc": true, "mb": true, "nb": true, "nl": true, "ns": true, "nu": true, "on": true,
+	"pe": true, "qc": true, "sk": true, "yt": true,
+}
+
+var (
+	poBoxPattern    = regexp.MustCompile(`(?i)\b(?:(?:p\s*o|post\s+office|g\s*p\s*o)\s*box|locked\s+bag|pmb)\s*#?\s*(\d+)\b`)
+	unitPattern     = regexp.MustCompile(`(?i)(?:\b(apt|apartment|unit|suite|ste|flat|floor|rm|room|lot|shop)\b|(#))\s*#?\s*([a-z]?\d+[a-z]?|[a-z])\b`)
+	ordinalPattern  = regexp.MustCompile(`(?i)^\d+(st|nd|rd|th)$`)
+	slashUnit       = regexp.MustCompile(`(?i)^\s*(?:(apt|apartment|unit|suite|ste|flat|shop)\s*)?([a-z]?\d+[a-z]?)\s*/\s*(\d+[a-z]?)\b`)
+	leadingNumber   = regexp.MustCompile(`^[\s,;]*\d`)
+	abbreviationDot = regexp.MustCompile(`([A-Za-z])\.`)
+	separators      = regexp.MustCompile(`\s*[,;]+\s*`)
+)
+
+// Address is a normalized address
+type Address struct {
+	// Original is the input address
+	Original string
+	// Query is the normalized address for forward geocoding, with unit and PO box designators removed
+	Query string
+	// UnitDesignator is the canonical unit designator (eg. Apt, Unit, Suite)
+	UnitDesignator string
+	// Unit is the unit, apartment or suite number
+	Unit string
+	// POBox is the PO box number, PO boxes cannot be geocoded to a street location
+	POBox string
+	// Postcode is the canonical postcode, if found
+	Postcode string
+	// Country is the country used for postcode matching
+	Country string
+}
+
+// IsPOBox indicates the address is a PO box
+func (a *Address) IsPOBox() bool {
+	return a.POBox != ""
+}
+
+// Normalize normalizes an address for geocoding
+// Country is an optional ISO 3166-1 alpha-2 country code used for postcode extraction
+func Normalize(raw, country string) *Address {
+	a := &Address{Original: raw, Country: strings.ToLower(country)}
+
+	s := strings.Join(strings.Fields(raw), " ")
+
+	// Remove abbreviation periods (eg. P.O. -> PO, St. -> St)
+	s = abbreviationDot.ReplaceAllString(s, "$1")
+
+	// Extract PO boxes
+	if m := poBoxPattern.FindStringSubmatchIndex(s); m != nil {
+		a.POBox = s[m[2]:m[3]]
+		s = s[:m[0]] + " " + s[m[1]:]
+	}
+
+	// Extract units (eg. 3/12 Smith St or Unit 3/12 Smith St)
+	if m := slashUnit.FindStringSubmatchIndex(s); m != nil {
+		a.UnitDesignator, a.Unit = "Unit", strings.ToUpper(s[m[4]:m[5]])
+		if m[2] >= 0 {
+			a.UnitDesignator = UnitDesignators[strings.ToLower(s[m[2]:m[3]])]
+		}
+		s = s[m[6]:m[7]] + s[m[1]:]
+	} else if m := unitPattern.FindStringSubmatchIndex(s); m != nil {
+		designator := ""
+		if m[2] >= 0 {
+			designator = s[m[2]:m[3]]
+		} else {
+			designator = s[m[4]:m[5]]
+		}
+		a.UnitDesignator = UnitDesignators[strings.ToLower(designator)]
+		a.Unit = strings.ToUpper(s[m[6]:m[7]])
+
+		// Lot numbers are the address number unless a street number follows (eg. Lot 5 Smith Rd)
+		if a.UnitDesignator == "Lot" && !leadingNumber.MatchString(s[m[1]:]) {
+			s = s[:m[0]] + s[m[6]:m[7]] + s[m[1]:]
+		} else {
+			s = s[:m[0]] + " " + s[m[1]:]
+		}
+	}
+
+	// Canonicalize postcodes in place
+	if start, end, postcode, ok := findPostcode(s, a.Country); ok {
+		a.Postcode = postcode
+		s = s[:start] + postcode + s[end:]
+	}
+
+	// Split into comma separated parts and normalize each
+	parts := []string{}
+	for _, p := range separators.Split(s, -1) {
+		if p = normalizePart(p, a.Postcode, len(parts) == 0); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	a.Query = strings.Join(parts, ", ")
+
+	return a
+}
+
+// normalizePart normalizes the words of a single comma separated address part
+// Street indicates the first (street) part, where state codes are matched more strictly
+func normalizePart(part, postcode string, street bool) string {
+	words := strings.Fields(strings.Trim(part, " -"))
+	out := make([]string, 0, len(words))
+
+	for i, w := range words {
+		lower := strings.ToLower(w)
+		prev, next, after := "", "", ""
+		if i > 0 {
+			prev = words[i-1]
+		}
+		if i < len(words)-1 {
+			next = words[i+1]
+		}
+		if i < len(words)-2 {
+			after = words[i+2]
+		}
+
+		// A directional followed only by a street type is the street name (eg. 123 N St, 12 E St NW)
+		streetName := isStreetType(next) && (after == "" || Directionals[strings.ToLower(after)] != "")
+
+		// State codes end a part or precede a postcode, which may not have been extracted without a country.
+		// In the street part they must follow a word and precede a final postcode (eg. Dubbo nsw 2830)
+		postcodeNext := isNumeric(next) || (postcode != "" && strings.EqualFold(next, postcode))
+		state := StateCodes[lower] && ((!street && (next == "" || postcodeNext)) || (prev != "" && postcodeNext && after == ""))
+
+		switch {
+		case postcode != "" && strings.EqualFold(w, postcode):
+			out = append(out, postcode)
+
+		case lower == "st" && (prev == "" || isNumeric(prev)) && isAlpha(next):
+			// St preceding a name is Saint (eg. 12 St Kilda Rd)
+			out = append(out, "Saint")
+
+		case StreetTypes[lower] != "" && prev != "" && !isNumeric(prev):
+			out = append(out, StreetTypes[lower])
+
+		case Directionals[lower] != "" && len(words) > 1 && !streetName && (isNumeric(prev) || isStreetType(prev) || (prev == "" && isAlpha(next))):
+			out = append(out, Directionals[lower])
+
+		case state:
+			out = append(out, strings.ToUpper(w))
+
+		default:
+			out = append(out, normalizeCase(w))
+		}
+	}
+
+	return strings.Join(out, " ")
+}
+
+func isStreetType(w string) bool {
+	lower := strings.ToLower(w)
+	if _, ok := StreetTypes[lower]; ok {
+		return true
+	}
+	for _, v := range StreetTypes {
+		if strings.EqualFold(v, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumeric(w string) bool {
+	if w == "" {
+		return false
+	}
+	for _, r := range w {
+		if !unicode.IsDigit(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlpha(w string) bool {
+	if w == "" {
+		return false
+	}
+	for _, r := range w {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeCase title cases single case words, preserving mixed case words (eg. McDonald),
+// short upper case codes (eg. NY) and words containing digits (eg. 4B, 5th)
+func normalizeCase(w string) string {
+	if ordinalPattern.MatchString(w) {
+		return strings.ToLower(w)
+	}
+
+	hasUpper, hasLower, hasDigit := false, false, false
+	for _, r := range w {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	switch {
+	case hasDigit:
+		return strings.ToUpper(w)
+	case hasUpper && hasLower:
+		return w
+	case hasUpper && len([]rune(w)) <= 3:
+		return w
+	}
+
+	runes := []rune(strings.ToLower(w))
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == '-' || runes[i-1] == '\'' && i == 2 {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,122 @@
+/**
+ * go-mapbox Address Normalization Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+
+	tests := []struct {
+		name, raw, country string
+		expected           Address
+	}{
+		{
+			"Expands street types and directionals", "2 lincoln memorial cir.  NW,washington ,DC 20037", "us",
+			Address{Query: "2 Lincoln Memorial Circle Northwest, Washington, DC 20037", Postcode: "20037"},
+		},
+		{
+			"Distinguishes saints from streets", "12 St. Kilda Rd, St Kilda VIC 3182", "au",
+			Address{Query: "12 Saint Kilda Road, Saint Kilda VIC 3182", Postcode: "3182"},
+		},
+		{
+			"Extracts apartments", "100 N MAIN ST APT 4b, SPRINGFIELD, IL 62701-1234", "us",
+			Address{Query: "100 North Main Street, Springfield, IL 62701-1234", UnitDesignator: "Apt", Unit: "4B", Postcode: "62701-1234"},
+		},
+		{
+			"Extracts suites and hash units", "350 5th Ave #3400, New York, NY 10118", "us",
+			Address{Query: "350 5th Avenue, New York, NY 10118", UnitDesignator: "#", Unit: "3400", Postcode: "10118"},
+		},
+		{
+			"Extracts slash units", "3/45 smith st, auckland 1010", "nz",
+			Address{Query: "45 Smith Street, Auckland 1010", UnitDesignator: "Unit", Unit: "3", Postcode: "1010"},
+		},
+		{
+			"Extracts designated slash units", "Unit 3/12 Smith St, Melbourne VIC 3000", "au",
+			Address{Query: "12 Smith Street, Melbourne VIC 3000", UnitDesignator: "Unit", Unit: "3", Postcode: "3000"},
+		},
+		{
+			"Keeps lot numbers as address numbers", "Lot 5 Smith Rd, Springfield", "",
+			Address{Query: "5 Smith Road, Springfield", UnitDesignator: "Lot", Unit: "5"},
+		},
+		{
+			"Extracts lots with street numbers", "Lot 5, 12 Smith Rd, Springfield", "",
+			Address{Query: "12 Smith Road, Springfield", UnitDesignator: "Lot", Unit: "5"},
+		},
+		{
+			"Preserves directional street names", "123 N St, washington, dc 20001", "us",
+			Address{Query: "123 N Street, Washington, DC 20001", Postcode: "20001"},
+		},
+		{
+			"Preserves directional street names with suffixes", "12 e st nw, washington", "us",
+			Address{Query: "12 E Street Northwest, Washington"},
+		},
+		{
+			"Upper cases state codes before postcodes without a country", "123 Main St, Springfield, il 62704", "",
+			Address{Query: "123 Main Street, Springfield, IL 62704"},
+		},
+		{
+			"Upper cases state codes without postcodes", "123 Main St, springfield, il", "",
+			Address{Query: "123 Main Street, Springfield, IL"},
+		},
+		{
+			"Upper cases state codes in a single part", "Dubbo nsw 2830", "",
+			Address{Query: "Dubbo NSW 2830"},
+		},
+		{
+			"Extracts PO boxes", "P.O. Box 1234; Ottawa ON k1a0b1", "ca",
+			Address{Query: "Ottawa ON K1A 0B1", POBox: "1234", Postcode: "K1A 0B1"},
+		},
+		{
+			"Canonicalizes UK postcodes", "10 downing st, london sw1a2aa", "gb",
+			Address{Query: "10 Downing Street, London SW1A 2AA", Postcode: "SW1A 2AA"},
+		},
+		{
+			"Ignores leading house numbers as postcodes", "12345 McDonald Blvd", "us",
+			Address{Query: "12345 McDonald Boulevard"},
+		},
+		{
+			"Preserves state codes", "1 Main St, Omaha, NE 68102", "us",
+			Address{Query: "1 Main Street, Omaha, NE 68102", Postcode: "68102"},
+		},
+		{
+			"Works without a country", "  o'brien  st,  dublin ", "",
+			Address{Query: "O'Brien Street, Dublin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Normalize(tt.raw, tt.country)
+
+			tt.expected.Original = tt.raw
+			tt.expected.Country = tt.country
+			assert.EqualValues(t, tt.expected, *a)
+		})
+	}
+
+	t.Run("Identifies PO boxes", func(t *testing.T) {
+		assert.True(t, Normalize("PO Box 5, Wellington", "nz").IsPOBox())
+		assert.False(t, Normalize("5 Post Office Rd, Wellington", "nz").IsPOBox())
+	})
+
+	t.Run("Normalizes standalone postcodes", func(t *testing.T) {
+		p, err := NormalizePostcode("1012ab", "NL")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "1012 AB", p)
+
+		_, err = NormalizePostcode("ABCDE", "us")
+		assert.NotNil(t, err)
+
+		_, err = NormalizePostcode("12345", "zz")
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,116 @@
+/**
+ * go-mapbox Address Normalization Postcodes
+ * Per-country postcode patterns for extracting and canonicalizing postcodes
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package address
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PostcodePattern matches and formats postcodes for a country
+type PostcodePattern struct {
+	// Pattern matches a postcode, with capture groups for each component
+	Pattern *regexp.Regexp
+	// Format builds the canonical postcode from the matched components
+	Format func(parts []string) string
+}
+
+func joinUpper(sep string) func(parts []string) string {
+	return func(parts []string) string {
+		components := []string{}
+		for _, p := range parts {
+			if p != "" {
+				components = append(components, strings.ToUpper(p))
+			}
+		}
+		return strings.Join(components, sep)
+	}
+}
+
+var (
+	fourDigits = &PostcodePattern{regexp.MustCompile(`\b(\d{4})\b`), joinUpper("")}
+	fiveDigits = &PostcodePattern{regexp.MustCompile(`\b(\d{5})\b`), joinUpper("")}
+)
+
+// Postcodes are the postcode patterns by lower case ISO 3166-1 alpha-2 country code
+// Additional countries can be added by callers before use
+var Postcodes = map[string]*PostcodePattern{
+	"us": {regexp.MustCompile(`\b(\d{5})(?:[ -]?(\d{4}))?\b`), joinUpper("-")},
+	"ca": {regexp.MustCompile(`(?i)\b([a-z]\d[a-z])\s?(\d[a-z]\d)\b`), joinUpper(" ")},
+	"gb": {regexp.MustCompile(`(?i)\b([a-z]{1,2}\d[a-z\d]?)\s?(\d[a-z]{2})\b`), joinUpper(" ")},
+	"ie": {regexp.MustCompile(`(?i)\b([a-z]\d[\dw])\s?([a-z\d]{4})\b`), joinUpper(" ")},
+	"nl": {regexp.MustCompile(`(?i)\b(\d{4})\s?([a-z]{2})\b`), joinUpper(" ")},
+	"jp": {regexp.MustCompile(`\b(\d{3})-?(\d{4})\b`), joinUpper("-")},
+	"br": {regexp.MustCompile(`\b(\d{5})-?(\d{3})\b`), joinUpper("-")},
+	"au": fourDigits,
+	"nz": fourDigits,
+	"at": fourDigits,
+	"be": fourDigits,
+	"ch": fourDigits,
+	"dk": fourDigits,
+	"no": fourDigits,
+	"za": fourDigits,
+	"de": fiveDigits,
+	"fr": fiveDigits,
+	"es": fiveDigits,
+	"it": fiveDigits,
+	"mx": fiveDigits,
+	"fi": fiveDigits,
+}
+
+// findPostcode finds the last postcode in a string that is not at the start of the string
+// (where it is more likely to be a house number), returning the match indices and canonical postcode
+func findPostcode(s, country string) (int, int, string, bool) {
+	p, ok := Postcodes[strings.ToLower(country)]
+	if !ok {
+		return 0, 0, "", false
+	}
+
+	matches := p.Pattern.FindAllStringSubmatchIndex(s, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		if m[0] == 0 {
+			continue
+		}
+
+		parts := []string{}
+		for g := 2; g < len(m); g += 2 {
+			if m[g] >= 0 {
+				parts = append(parts, s[m[g]:m[g+1]])
+			}
+		}
+		return m[0], m[1], p.Format(parts), true
+	}
+
+	return 0, 0, "", false
+}
+
+// NormalizePostcode validates and canonicalizes a standalone postcode for a country
+func NormalizePostcode(postcode, country string) (string, error) {
+	p, ok := Postcodes[strings.ToLower(country)]
+	if !ok {
+		return "", fmt.Errorf("No postcode pattern for country '%s'", country)
+	}
+
+	postcode = strings.TrimSpace(postcode)
+	m := p.Pattern.FindStringSubmatchIndex(postcode)
+	if m == nil || m[0] != 0 || m[1] != len(postcode) {
+		return "", fmt.Errorf("Invalid postcode '%s' for country '%s'", postcode, country)
+	}
+
+	parts := []string{}
+	for g := 2; g < len(m); g += 2 {
+		if m[g] >= 0 {
+			parts = append(parts, postcode[m[g]:m[g+1]])
+		}
+	}
+
+	return p.Format(parts), nil
+}
@@ -10,6 +10,7 @@
 package base
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 )
 
 const (
@@ -28,8 +30,10 @@ const (
 
 // Base Mapbox API base
 type Base struct {
-	token string
-	debug bool
+	token   string
+	debug   bool
+	baseURL string
+	backend Backend
 }
 
 // NewBase Create a new API base instance
@@ -41,6 +45,8 @@ func NewBase(token string) (*Base, error) {
 	b := &Base{}
 
 	b.token = token
+	b.baseURL = BaseURL
+	b.backend = BackendMapbox
 
 	return b, nil
 }
@@ -50,24 +56,36 @@ func (b *Base) SetDebug(debug bool) {
 	b.debug = true
 }
 
+// SetBaseURL overrides the API base URL, for use with proxies or stand-in servers
+func (b *Base) SetBaseURL(baseURL string) {
+	b.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
 type MapboxApiMessage struct {
 	Message string
 }
 
 // QueryRequest make a get with the provided query string and return the response if successful
 func (b *Base) QueryRequest(query string, v *url.Values) (*http.Response, error) {
-	// Add token to args
-	v.Set("access_token", b.token)
+	return b.QueryRequestContext(context.Background(), query, v)
+}
+
+// QueryRequestContext makes a QueryRequest that is aborted when the provided context is cancelled
+func (b *Base) QueryRequestContext(ctx context.Context, query string, v *url.Values) (*http.Response, error) {
+	// Add token to args (self hosted backends have no token)
+	if b.token != "" {
+		v.Set("access_token", b.token)
+	}
 
 	// Generate URL
-	url := fmt.Sprintf("%s/%s", BaseURL, query)
+	url := fmt.Sprintf("%s/%s", b.baseURL, query)
 
 	if b.debug {
 		fmt.Printf("URL: %s\n", url)
 	}
 
 	// Create request object
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +112,10 @@ func (b *Base) QueryRequest(query string, v *url.Values) (*http.Response, error)
 	if resp.StatusCode == http.StatusUnauthorized {
 		return nil, ErrorAPIUnauthorized
 	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		resp.Body.Close()
+		return nil, ErrorAPIServer
+	}
 
 	return resp, nil
 }
@@ -101,8 +123,13 @@ func (b *Base) QueryRequest(query string, v *url.Values) (*http.Response, error)
 // QueryBase Query the mapbox API and fill the provided instance with the returned JSON
 // TODO: Rename this
 func (b *Base) QueryBase(query string, v *url.Values, inst interface{}) error {
+	return b.QueryBaseContext(context.Background(), query, v, inst)
+}
+
+// QueryBaseContext makes a QueryBase request that is aborted when the provided context is cancelled
+func (b *Base) QueryBaseContext(ctx context.Context, query string, v *url.Values, inst interface{}) error {
 	// Make request
-	resp, err := b.QueryRequest(query, v)
+	resp, err := b.QueryRequestContext(ctx, query, v)
 	if err != nil && (resp == nil || resp.StatusCode != http.StatusBadRequest) {
 		return err
 	}
@@ -136,9 +163,14 @@ func (b *Base) QueryBase(query string, v *url.Values, inst interface{}) error {
 // Query the mapbox API
 // TODO: Depreciate this
 func (b *Base) Query(api, version, mode, query string, v *url.Values, inst interface{}) error {
+	return b.QueryContext(context.Background(), api, version, mode, query, v, inst)
+}
+
+// QueryContext makes a Query that is aborted when the provided context is cancelled
+func (b *Base) QueryContext(ctx context.Context, api, version, mode, query string, v *url.Values, inst interface{}) error {
 
 	// Generate URL
 	queryString := fmt.Sprintf("%s/%s/%s/%s", api, version, mode, query)
 
-	return b.QueryBase(queryString, v, inst)
+	return b.QueryBaseContext(ctx, queryString, v, inst)
 }
@@ -0,0 +1,64 @@
+/**
+ * go-mapbox Base Module Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryErrors(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/invalid":
+			http.Error(w, `{"message": "Invalid coordinates"}`, http.StatusBadRequest)
+		case "/unavailable":
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		case "/failed":
+			http.Error(w, `{"message": "Internal error"}`, http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"code": "Ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	b, err := NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+
+	query := func(path string) error {
+		resp := struct{ Code string }{}
+		return b.QueryBase(path, &url.Values{}, &resp)
+	}
+
+	t.Run("Decodes successful responses", func(t *testing.T) {
+		assert.Nil(t, query("ok"))
+	})
+
+	t.Run("Returns errors for client failures", func(t *testing.T) {
+		assert.Equal(t, ErrorAPILimitExceeded, query("limited"))
+		assert.Equal(t, ErrorAPIUnauthorized, query("unauthorized"))
+		assert.EqualError(t, query("invalid"), "api error: Invalid coordinates")
+	})
+
+	t.Run("Returns server errors", func(t *testing.T) {
+		assert.Equal(t, ErrorAPIServer, query("unavailable"))
+		assert.Equal(t, ErrorAPIServer, query("failed"))
+	})
+}
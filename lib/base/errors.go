@@ -18,3 +18,6 @@ var ErrorAPIUnauthorized = errors.New("Mapbox API error unauthorized")
 
 // ErrorAPILimitExceeded indicates the API limit has been exceeded
 var ErrorAPILimitExceeded = errors.New("Mapbox API error api rate limit exceeded")
+
+// ErrorAPIServer indicates the API failed with a server error
+var ErrorAPIServer = errors.New("Mapbox API error server error")
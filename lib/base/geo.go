@@ -0,0 +1,80 @@
+/**
+ * go-mapbox Base Module Geo Helpers
+ * Provides common geographic calculations for API modules
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"math"
+)
+
+// EarthRadius is the mean radius of the earth in meters
+const EarthRadius = 6371008.8
+
+// Distance calculates the great circle (haversine) distance between two locations in meters
+func Distance(a, b Location) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// PointToLocation converts a [lng, lat] point to a location
+func PointToLocation(p Point) Location {
+	if len(p) < 2 {
+		return Location{}
+	}
+	return Location{Latitude: p[1], Longitude: p[0]}
+}
+
+// Contains checks whether a [minLng, minLat, maxLng, maxLat] bounding box contains a location
+func (b BoundingBox) Contains(loc Location) bool {
+	if len(b) != 4 {
+		return false
+	}
+	return loc.Longitude >= b[0] && loc.Latitude >= b[1] && loc.Longitude <= b[2] && loc.Latitude <= b[3]
+}
+
+// PathLength calculates the length of a path in meters
+func PathLength(path []Location) float64 {
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		length += Distance(path[i-1], path[i])
+	}
+	return length
+}
+
+// Interpolate returns the location a fraction (0 to 1) of the way from a to b
+// This uses linear interpolation, which is suitable for the short segments of a path
+func Interpolate(a, b Location, fraction float64) Location {
+	return Location{
+		Latitude:  a.Latitude + (b.Latitude-a.Latitude)*fraction,
+		Longitude: a.Longitude + (b.Longitude-a.Longitude)*fraction,
+	}
+}
+
+// NearestOnSegment finds the nearest location to p on the segment from a to b, returning the
+// location and the fraction (0 to 1) of the way along the segment
+// This uses a local equirectangular projection, which is suitable for the short segments of a path
+func NearestOnSegment(p, a, b Location) (Location, float64) {
+	scale := math.Cos(p.Latitude * math.Pi / 180)
+
+	dx, dy := (b.Longitude-a.Longitude)*scale, b.Latitude-a.Latitude
+	px, py := (p.Longitude-a.Longitude)*scale, p.Latitude-a.Latitude
+
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return a, 0
+	}
+
+	f := math.Max(0, math.Min(1, (px*dx+py*dy)/length))
+
+	return Interpolate(a, b, f), f
+}
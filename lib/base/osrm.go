@@ -0,0 +1,80 @@
+/**
+ * go-mapbox Base Module OSRM Backend
+ * Allows routing modules to target a self hosted OSRM server, which uses the same response format
+ * See http://project-osrm.org/docs/v5.24.0/api/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Backend is the type of server an API base targets
+type Backend string
+
+const (
+	// BackendMapbox targets the Mapbox API
+	BackendMapbox Backend = "mapbox"
+	// BackendOSRM targets a self hosted OSRM server, supporting only the routing modules
+	BackendOSRM Backend = "osrm"
+)
+
+// OSRMVersion is the OSRM HTTP API version
+const OSRMVersion = "v1"
+
+// OSRMService describes the OSRM service equivalent to a Mapbox API
+type OSRMService struct {
+	// Name is the service path component (eg. route, table, match)
+	Name string
+	// Params are the query parameters supported by the service
+	Params []string
+}
+
+// NewOSRMBase creates an API base targeting a self hosted OSRM server at the provided URL
+func NewOSRMBase(baseURL string) *Base {
+	b := &Base{backend: BackendOSRM}
+	b.SetBaseURL(baseURL)
+	return b
+}
+
+// Backend returns the type of server the base targets
+func (b *Base) Backend() Backend {
+	return b.backend
+}
+
+// OSRMProfile converts a Mapbox routing profile (eg. mapbox/driving) into an OSRM profile (eg. driving)
+func OSRMProfile(profile string) string {
+	return strings.TrimPrefix(profile, "mapbox/")
+}
+
+// QueryRouting queries a routing API, using the equivalent OSRM service path and profile for OSRM backends
+// Query parameters not supported by the OSRM service are rejected rather than silently ignored
+func (b *Base) QueryRouting(api, version string, service OSRMService, profile, query string, v *url.Values, inst interface{}) error {
+	if b.backend != BackendOSRM {
+		return b.Query(api, version, profile, query, v, inst)
+	}
+
+	supported := make(map[string]bool, len(service.Params))
+	for _, p := range service.Params {
+		supported[p] = true
+	}
+	unsupported := []string{}
+	for k := range *v {
+		if !supported[k] {
+			unsupported = append(unsupported, k)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("OSRM %s service does not support options: %s", service.Name, strings.Join(unsupported, ", "))
+	}
+
+	return b.Query(service.Name, OSRMVersion, OSRMProfile(profile), query, v, inst)
+}
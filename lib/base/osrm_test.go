@@ -0,0 +1,73 @@
+/**
+ * go-mapbox Base Module OSRM Backend Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSRM(t *testing.T) {
+
+	requests := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		w.Write([]byte(`{"code":"Ok"}`))
+	}))
+	defer server.Close()
+
+	service := OSRMService{Name: "route", Params: []string{"steps"}}
+	resp := struct{ Code string }{}
+
+	t.Run("Queries the OSRM service path without a token", func(t *testing.T) {
+		b := NewOSRMBase(server.URL + "/")
+		assert.Equal(t, BackendOSRM, b.Backend())
+
+		v := url.Values{}
+		v.Set("steps", "true")
+		err := b.QueryRouting("directions", "v5", service, "mapbox/driving", "1,2;3,4", &v, &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, "Ok", resp.Code)
+
+		r := <-requests
+		assert.Equal(t, "/route/v1/driving/1,2;3,4", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("steps"))
+		_, hasToken := r.URL.Query()["access_token"]
+		assert.False(t, hasToken)
+	})
+
+	t.Run("Rejects unsupported options", func(t *testing.T) {
+		b := NewOSRMBase(server.URL)
+
+		v := url.Values{}
+		v.Set("voice_instructions", "true")
+		v.Set("depart_at", "2017-01-01T00:00Z")
+		err := b.QueryRouting("directions", "v5", service, "mapbox/driving", "1,2;3,4", &v, &resp)
+		assert.EqualError(t, err, "OSRM route service does not support options: depart_at, voice_instructions")
+	})
+
+	t.Run("Queries the Mapbox API path with a token", func(t *testing.T) {
+		b, err := NewBase("test-token")
+		assert.Nil(t, err)
+		assert.Equal(t, BackendMapbox, b.Backend())
+		b.SetBaseURL(server.URL)
+
+		v := url.Values{}
+		v.Set("voice_instructions", "true")
+		err = b.QueryRouting("directions", "v5", service, "mapbox/driving", "1,2;3,4", &v, &resp)
+		assert.Nil(t, err)
+
+		r := <-requests
+		assert.Equal(t, "/directions/v5/mapbox/driving/1,2;3,4", r.URL.Path)
+		assert.Equal(t, "test-token", r.URL.Query().Get("access_token"))
+	})
+}
@@ -0,0 +1,109 @@
+/**
+ * go-mapbox Base Module Polylines
+ * Encodes and decodes polyline geometries as returned by the directions and map matching APIs
+ * See https://developers.google.com/maps/documentation/utilities/polylinealgorithm for format information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// PolylinePrecision5 is the precision of "polyline" geometries
+	PolylinePrecision5 = 5
+	// PolylinePrecision6 is the precision of "polyline6" geometries
+	PolylinePrecision6 = 6
+)
+
+// DecodePolyline decodes a polyline string with the provided precision into a list of locations
+func DecodePolyline(encoded string, precision int) ([]Location, error) {
+	factor := math.Pow10(precision)
+	locations := []Location{}
+
+	lat, lng := 0, 0
+	for i := 0; i < len(encoded); {
+		dLat, n, err := decodePolylineValue(encoded, i)
+		if err != nil {
+			return nil, err
+		}
+		i = n
+
+		dLng, n, err := decodePolylineValue(encoded, i)
+		if err != nil {
+			return nil, err
+		}
+		i = n
+
+		lat, lng = lat+dLat, lng+dLng
+		locations = append(locations, Location{Latitude: float64(lat) / factor, Longitude: float64(lng) / factor})
+	}
+
+	return locations, nil
+}
+
+func decodePolylineValue(encoded string, i int) (int, int, error) {
+	result, shift := 0, uint(0)
+
+	for {
+		if i >= len(encoded) {
+			return 0, 0, fmt.Errorf("Malformed polyline (unexpected end at index %d)", i)
+		}
+
+		b := int(encoded[i]) - 63
+		i++
+		if b < 0 || b > 63 {
+			return 0, 0, fmt.Errorf("Malformed polyline (invalid character at index %d)", i-1)
+		}
+
+		result |= (b & 0x1f) << shift
+		shift += 5
+
+		if b < 0x20 {
+			break
+		}
+	}
+
+	if result&1 != 0 {
+		return ^(result >> 1), i, nil
+	}
+	return result >> 1, i, nil
+}
+
+// EncodePolyline encodes a list of locations into a polyline string with the provided precision
+func EncodePolyline(locations []Location, precision int) string {
+	factor := math.Pow10(precision)
+	var sb strings.Builder
+
+	prevLat, prevLng := 0, 0
+	for _, l := range locations {
+		lat := int(math.Round(l.Latitude * factor))
+		lng := int(math.Round(l.Longitude * factor))
+
+		encodePolylineValue(&sb, lat-prevLat)
+		encodePolylineValue(&sb, lng-prevLng)
+
+		prevLat, prevLng = lat, lng
+	}
+
+	return sb.String()
+}
+
+func encodePolylineValue(sb *strings.Builder, v int) {
+	v <<= 1
+	if v < 0 {
+		v = ^v
+	}
+
+	for v >= 0x20 {
+		sb.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	sb.WriteByte(byte(v + 63))
+}
@@ -0,0 +1,50 @@
+/**
+ * go-mapbox Base Module Polyline Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolyline(t *testing.T) {
+
+	path := []Location{{Latitude: 38.5, Longitude: -120.2}, {Latitude: 40.7, Longitude: -120.95}, {Latitude: 43.252, Longitude: -126.453}}
+	encoded := "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+	t.Run("Decodes polylines", func(t *testing.T) {
+		decoded, err := DecodePolyline(encoded, PolylinePrecision5)
+		assert.Nil(t, err)
+		if assert.Len(t, decoded, len(path)) {
+			for i := range path {
+				assert.InDelta(t, path[i].Latitude, decoded[i].Latitude, 1e-9)
+				assert.InDelta(t, path[i].Longitude, decoded[i].Longitude, 1e-9)
+			}
+		}
+	})
+
+	t.Run("Encodes polylines", func(t *testing.T) {
+		assert.EqualValues(t, encoded, EncodePolyline(path, PolylinePrecision5))
+
+		decoded, err := DecodePolyline(EncodePolyline(path, PolylinePrecision6), PolylinePrecision6)
+		assert.Nil(t, err)
+		assert.InDelta(t, path[2].Longitude, decoded[2].Longitude, 1e-9)
+	})
+
+	t.Run("Rejects malformed polylines", func(t *testing.T) {
+		_, err := DecodePolyline("_p~iF~ps|U_ulL", PolylinePrecision5)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Calculates path lengths", func(t *testing.T) {
+		// One degree of latitude is ~111.2km
+		length := PathLength([]Location{{Latitude: 0, Longitude: 0}, {Latitude: 1, Longitude: 0}})
+		assert.InDelta(t, 111195, length, 10)
+	})
+}
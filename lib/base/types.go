@@ -45,6 +45,7 @@ type Feature struct {
 	PlaceName  string      `json:"place_name"`
 	PlaceType  []string    `json:"place_type"`
 	Relevance  float64     `json:"relevance"`
+	Address    string      `json:"address"`
 	Properties Properties  `json:"properties"`
 	BBox       BoundingBox `json:"bbox"`
 	Center     Point       `json:"center"`
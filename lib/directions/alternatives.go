@@ -0,0 +1,210 @@
+/**
+ * go-mapbox Directions Module Alternatives
+ * Compares alternative routes and selects between them using a cost function
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// DefaultSharedTolerance is the default distance in meters within which geometry is shared with the primary route
+const DefaultSharedTolerance = 15.0
+
+// CongestionWeights are the duration multipliers for each congestion level when calculating
+// congestion weighted durations, unlisted levels have a weight of 1
+var CongestionWeights = map[CongestionLevel]float64{
+	CongestionModerate: 1.25,
+	CongestionHeavy:    1.5,
+	CongestionSevere:   2,
+}
+
+// AlternativeOpts options for comparing alternative routes
+type AlternativeOpts struct {
+	// Geometry is the geometry type the routes were requested with
+	Geometry GeometryType
+	// Tolerance is the distance in meters within which geometry is shared with the primary route
+	Tolerance float64
+}
+
+// AlternativeAnalysis compares an alternative route with the primary route
+type AlternativeAnalysis struct {
+	// Index is the index of the route in the response, 0 being the primary route
+	Index int
+	Route *Route
+	// SharedDistance and UniqueDistance are the lengths in meters of geometry shared with
+	// and unique from the primary route
+	SharedDistance, UniqueDistance float64
+	// TollShare, FerryShare and MotorwayShare are the fractions of the route distance on each road class
+	TollShare, FerryShare, MotorwayShare float64
+	// CongestionDuration is the duration in seconds weighted by congestion annotations (see CongestionWeights),
+	// or the route duration where congestion annotations are not available
+	CongestionDuration float64
+	// Maneuvers is the number of maneuvers, excluding departure, arrival and name changes
+	Maneuvers int
+}
+
+// SharedFraction is the fraction of the route geometry shared with the primary route
+func (a *AlternativeAnalysis) SharedFraction() float64 {
+	total := a.SharedDistance + a.UniqueDistance
+	if total == 0 {
+		return 0
+	}
+	return a.SharedDistance / total
+}
+
+// CostFunc calculates the cost of a route for selection, lower costs are preferred
+type CostFunc func(a *AlternativeAnalysis) float64
+
+// AnalyzeAlternatives compares each route with the first (primary) route
+// Road class shares require steps, and congestion weighting requires congestion and duration annotations
+func AnalyzeAlternatives(routes []Route, opts *AlternativeOpts) ([]AlternativeAnalysis, error) {
+	o := AlternativeOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = DefaultSharedTolerance
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("No routes to analyze")
+	}
+
+	primary, err := routes[0].GetPath(o.Geometry)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses := make([]AlternativeAnalysis, len(routes))
+	for i := range routes {
+		r := &routes[i]
+		a := &analyses[i]
+		a.Index, a.Route = i, r
+
+		path, err := r.GetPath(o.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		a.SharedDistance, a.UniqueDistance = sharedDistance(path, primary, o.Tolerance)
+
+		a.analyzeSteps()
+		a.CongestionDuration = congestionDuration(r)
+	}
+
+	return analyses, nil
+}
+
+// SelectAlternative returns the analysis with the lowest cost, nil if there are none
+func SelectAlternative(analyses []AlternativeAnalysis, cost CostFunc) *AlternativeAnalysis {
+	var best *AlternativeAnalysis
+	bestCost := 0.0
+	for i := range analyses {
+		c := cost(&analyses[i])
+		if best == nil || c < bestCost {
+			best, bestCost = &analyses[i], c
+		}
+	}
+	return best
+}
+
+// analyzeSteps calculates road class shares and maneuvers from the route steps
+// Step distance is divided evenly between the intersections of a step, with each intersection
+// contributing the classes of the road exiting it
+func (a *AlternativeAnalysis) analyzeSteps() {
+	total, toll, ferry, motorway := 0.0, 0.0, 0.0, 0.0
+
+	for _, leg := range a.Route.Legs {
+		for _, step := range leg.Steps {
+			total += step.Distance
+
+			switch step.Maneuver.Type {
+			case ManeuverDepart, ManeuverArrive, ManeuverNewName, ManeuverNotification:
+			default:
+				a.Maneuvers++
+			}
+
+			if step.Mode == ModeFerry {
+				ferry += step.Distance
+				continue
+			}
+			if len(step.Intersections) == 0 {
+				continue
+			}
+
+			share := step.Distance / float64(len(step.Intersections))
+			for _, in := range step.Intersections {
+				for _, c := range in.Classes {
+					switch c {
+					case ClassToll:
+						toll += share
+					case ClassFerry:
+						ferry += share
+					case ClassMotorway:
+						motorway += share
+					}
+				}
+			}
+		}
+	}
+
+	if total > 0 {
+		a.TollShare, a.FerryShare, a.MotorwayShare = toll/total, ferry/total, motorway/total
+	}
+}
+
+// congestionDuration weights leg annotation durations by congestion
+func congestionDuration(r *Route) float64 {
+	weighted := 0.0
+	for _, leg := range r.Legs {
+		a := leg.Annotation
+		if len(a.Duration) == 0 || len(a.Duration) != len(a.Congestion) {
+			weighted += leg.Duration
+			continue
+		}
+		for i, d := range a.Duration {
+			w, ok := CongestionWeights[a.Congestion[i]]
+			if !ok {
+				w = 1
+			}
+			weighted += d * w
+		}
+	}
+	if len(r.Legs) == 0 {
+		return r.Duration
+	}
+	return weighted
+}
+
+// sharedDistance splits the length of a path into sections within the tolerance of another path and
+// sections that are not, testing the midpoint of each segment
+func sharedDistance(path, other []base.Location, tolerance float64) (float64, float64) {
+	shared, unique := 0.0, 0.0
+	for i := 1; i < len(path); i++ {
+		d := base.Distance(path[i-1], path[i])
+		if nearPath(base.Interpolate(path[i-1], path[i], 0.5), other, tolerance) {
+			shared += d
+		} else {
+			unique += d
+		}
+	}
+	return shared, unique
+}
+
+func nearPath(loc base.Location, path []base.Location, tolerance float64) bool {
+	if len(path) == 1 {
+		return base.Distance(loc, path[0]) <= tolerance
+	}
+	for i := 1; i < len(path); i++ {
+		nearest, _ := base.NearestOnSegment(loc, path[i-1], path[i])
+		if base.Distance(loc, nearest) <= tolerance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+/**
+ * go-mapbox Directions Module Alternatives Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestAlternatives(t *testing.T) {
+
+	encode := func(locs ...base.Location) string {
+		return base.EncodePolyline(locs, base.PolylinePrecision5)
+	}
+
+	a := base.Location{Latitude: 0, Longitude: 0}
+	b := base.Location{Latitude: 0, Longitude: 0.01}
+	c := base.Location{Latitude: 0, Longitude: 0.02}
+	detour := base.Location{Latitude: 0.01, Longitude: 0.015}
+
+	primary := Route{
+		Geometry: encode(a, b, c),
+		Duration: 100,
+		Legs: []RouteLeg{{
+			Duration: 100,
+			Steps: []RouteStep{
+				{Distance: 0, Maneuver: StepManeuver{Type: ManeuverDepart}},
+				{Distance: 1000, Maneuver: StepManeuver{Type: ManeuverTurn}, Intersections: []Intersection{
+					{Classes: []string{ClassMotorway, ClassToll}}, {Classes: []string{ClassMotorway}},
+				}},
+				{Distance: 1000, Maneuver: StepManeuver{Type: ManeuverNewName}, Intersections: []Intersection{{}}},
+				{Distance: 0, Maneuver: StepManeuver{Type: ManeuverArrive}},
+			},
+			Annotation: Annotation{
+				Duration:   []float64{50, 50},
+				Congestion: []CongestionLevel{CongestionLow, CongestionHeavy},
+			},
+		}},
+	}
+	alternative := Route{
+		Geometry: encode(a, b, detour, c),
+		Duration: 110,
+		Legs: []RouteLeg{{
+			Duration: 110,
+			Steps: []RouteStep{
+				{Distance: 0, Maneuver: StepManeuver{Type: ManeuverDepart}},
+				{Distance: 1000, Maneuver: StepManeuver{Type: ManeuverTurn}},
+				{Distance: 2000, Mode: ModeFerry, Maneuver: StepManeuver{Type: ManeuverTurn}},
+				{Distance: 1000, Maneuver: StepManeuver{Type: ManeuverFork}},
+				{Distance: 0, Maneuver: StepManeuver{Type: ManeuverArrive}},
+			},
+		}},
+	}
+
+	analyses, err := AnalyzeAlternatives([]Route{primary, alternative}, nil)
+	assert.Nil(t, err)
+	assert.Len(t, analyses, 2)
+
+	t.Run("Compares geometry with the primary route", func(t *testing.T) {
+		assert.Equal(t, 0.0, analyses[0].UniqueDistance)
+		assert.InDelta(t, 1.0, analyses[0].SharedFraction(), 1e-9)
+
+		assert.InDelta(t, base.Distance(a, b), analyses[1].SharedDistance, 1)
+		assert.InDelta(t, base.Distance(b, detour)+base.Distance(detour, c), analyses[1].UniqueDistance, 1)
+	})
+
+	t.Run("Calculates road class shares", func(t *testing.T) {
+		assert.InDelta(t, 0.5, analyses[0].MotorwayShare, 1e-9)
+		assert.InDelta(t, 0.25, analyses[0].TollShare, 1e-9)
+		assert.InDelta(t, 0.5, analyses[1].FerryShare, 1e-9)
+	})
+
+	t.Run("Weights durations by congestion", func(t *testing.T) {
+		assert.InDelta(t, 50+50*CongestionWeights[CongestionHeavy], analyses[0].CongestionDuration, 1e-9)
+		assert.InDelta(t, 110.0, analyses[1].CongestionDuration, 1e-9)
+	})
+
+	t.Run("Counts maneuvers", func(t *testing.T) {
+		assert.Equal(t, 1, analyses[0].Maneuvers)
+		assert.Equal(t, 3, analyses[1].Maneuvers)
+	})
+
+	t.Run("Selects routes by cost", func(t *testing.T) {
+		fastest := SelectAlternative(analyses, func(a *AlternativeAnalysis) float64 { return a.CongestionDuration })
+		assert.Equal(t, 1, fastest.Index)
+
+		noFerries := SelectAlternative(analyses, func(a *AlternativeAnalysis) float64 {
+			return a.CongestionDuration + 1000*a.FerryShare
+		})
+		assert.Equal(t, 0, noFerries.Index)
+
+		assert.Nil(t, SelectAlternative(nil, nil))
+	})
+
+	t.Run("Requires routes", func(t *testing.T) {
+		_, err := AnalyzeAlternatives(nil, nil)
+		assert.NotNil(t, err)
+	})
+}
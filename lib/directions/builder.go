@@ -0,0 +1,170 @@
+/**
+ * go-mapbox Directions Module Request Builder
+ * Builds validated direction requests from waypoints with per-waypoint options
+ * See https://www.mapbox.com/api-documentation/#retrieve-directions for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Bearing restricts the direction of travel at a waypoint
+type Bearing struct {
+	// Angle is the clockwise angle from true north in degrees (0 to 360)
+	Angle float64
+	// Deviation is the allowed deviation from the angle in degrees (0 to 180)
+	Deviation float64
+}
+
+// RequestWaypoint is a location in a directions request with per-waypoint options
+type RequestWaypoint struct {
+	Location base.Location
+	// Radius is the maximum snapping distance in meters, zero uses the API default
+	Radius float64
+	// RadiusUnlimited allows snapping to any distance, overriding Radius
+	RadiusUnlimited bool
+	// Bearing optionally restricts the direction of travel
+	Bearing *Bearing
+	// Approach optionally restricts the side of the road the waypoint is approached from
+	Approach ApproachType
+	// Name overrides the waypoint name used in instructions
+	Name string
+	// IncludeClosures and IncludeStaticClosures allow snapping to closed roads
+	IncludeClosures       bool
+	IncludeStaticClosures bool
+	// Silent waypoints shape the route without creating a stop or leg, the first and last waypoints cannot be silent
+	Silent bool
+}
+
+// RequestBuilder builds a validated directions request
+type RequestBuilder struct {
+	Profile     RoutingProfile
+	Waypoints   []RequestWaypoint
+	Annotations []AnnotationType
+	Exclude     []ExcludeType
+	// Opts sets request wide options, per-waypoint fields are overwritten by Build
+	Opts RequestOpts
+}
+
+// NewRequestBuilder creates a request builder for the provided routing profile
+func NewRequestBuilder(profile RoutingProfile) *RequestBuilder {
+	return &RequestBuilder{Profile: profile}
+}
+
+// Add appends a waypoint to the request
+func (b *RequestBuilder) Add(w RequestWaypoint) *RequestBuilder {
+	b.Waypoints = append(b.Waypoints, w)
+	return b
+}
+
+// AddLocation appends a waypoint with default options to the request
+func (b *RequestBuilder) AddLocation(loc base.Location) *RequestBuilder {
+	return b.Add(RequestWaypoint{Location: loc})
+}
+
+// Build validates the request and returns the locations and options for GetDirections
+func (b *RequestBuilder) Build() ([]base.Location, *RequestOpts, error) {
+	opts := b.Opts
+	if len(b.Annotations) > 0 {
+		opts.SetAnnotations(b.Annotations)
+	}
+	if len(b.Exclude) > 0 {
+		opts.SetExclude(b.Exclude)
+	}
+
+	n := len(b.Waypoints)
+	locations := make([]base.Location, n)
+	radiuses := make([]string, n)
+	bearings := make([]string, n)
+	approaches := make([]string, n)
+	names := []string{}
+	stops := []int{}
+	closures := make([]string, n)
+	staticClosures := make([]string, n)
+
+	hasRadius, hasBearing, hasApproach, hasName, hasClosures, hasStaticClosures, hasSilent := false, false, false, false, false, false, false
+
+	for i, w := range b.Waypoints {
+		locations[i] = w.Location
+
+		if w.RadiusUnlimited {
+			radiuses[i], hasRadius = string(RadiusUnlimited), true
+		} else if w.Radius != 0 {
+			radiuses[i], hasRadius = formatFloat(w.Radius), true
+		}
+
+		if w.Bearing != nil {
+			bearings[i], hasBearing = formatFloat(w.Bearing.Angle)+","+formatFloat(w.Bearing.Deviation), true
+		}
+
+		if w.Approach != "" {
+			approaches[i], hasApproach = string(w.Approach), true
+		}
+
+		if w.Silent {
+			if w.Name != "" {
+				return nil, nil, &ValueError{Field: "waypoint_names", Index: i, Value: w.Name, Reason: "silent waypoints cannot be named"}
+			}
+			hasSilent = true
+		} else {
+			if strings.Contains(w.Name, ";") {
+				return nil, nil, &ValueError{Field: "waypoint_names", Index: i, Value: w.Name, Reason: "must not contain ';'"}
+			}
+			stops = append(stops, i)
+			names = append(names, w.Name)
+			hasName = hasName || w.Name != ""
+		}
+
+		closures[i] = strconv.FormatBool(w.IncludeClosures)
+		hasClosures = hasClosures || w.IncludeClosures
+		staticClosures[i] = strconv.FormatBool(w.IncludeStaticClosures)
+		hasStaticClosures = hasStaticClosures || w.IncludeStaticClosures
+	}
+
+	opts.Radiuses = joinIf(hasRadius, radiuses)
+	opts.Bearings = joinIf(hasBearing, bearings)
+	opts.Approaches = joinIf(hasApproach, approaches)
+	opts.WaypointNames = joinIf(hasName, names)
+	opts.SnappingIncludeClosures = joinIf(hasClosures, closures)
+	opts.SnappingIncludeStaticClosures = joinIf(hasStaticClosures, staticClosures)
+	if hasSilent {
+		opts.SetWaypoints(stops)
+	}
+
+	if err := validateLocations(locations); err != nil {
+		return nil, nil, err
+	}
+	if err := opts.Validate(b.Profile, n); err != nil {
+		return nil, nil, err
+	}
+
+	return locations, &opts, nil
+}
+
+// GetDirectionsFor builds, validates and issues a directions request
+func (g *Directions) GetDirectionsFor(b *RequestBuilder) (*DirectionResponse, error) {
+	locations, opts, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return g.GetDirections(locations, b.Profile, opts)
+}
+
+func joinIf(set bool, values []string) string {
+	if !set {
+		return ""
+	}
+	return strings.Join(values, ";")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
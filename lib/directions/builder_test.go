@@ -0,0 +1,177 @@
+/**
+ * go-mapbox Directions Module Request Builder Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestRequestBuilder(t *testing.T) {
+
+	requests := 0
+	var lastQuery map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		lastQuery = r.URL.Query()
+		w.Write([]byte(`{"code":"Ok","routes":[{"distance":1200,"duration":300}]}`))
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+	d := NewDirections(b)
+
+	start := base.Location{Latitude: 38.8893, Longitude: -77.0502}
+	end := base.Location{Latitude: 38.8977, Longitude: -77.0365}
+
+	t.Run("Builds per-waypoint options", func(t *testing.T) {
+		builder := NewRequestBuilder(RoutingDriving)
+		builder.Annotations = []AnnotationType{AnnotationDistance, AnnotationSpeed}
+		builder.Exclude = []ExcludeType{ExcludeToll, ExcludeFerry}
+		builder.Add(RequestWaypoint{Location: start, Radius: 50, Bearing: &Bearing{Angle: 90, Deviation: 45}, Name: "Memorial"}).
+			Add(RequestWaypoint{Location: end, RadiusUnlimited: true, Approach: ApproachCurb, IncludeClosures: true})
+
+		_, opts, err := builder.Build()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "50;unlimited", opts.Radiuses)
+		assert.EqualValues(t, "90,45;", opts.Bearings)
+		assert.EqualValues(t, ";curb", opts.Approaches)
+		assert.EqualValues(t, "Memorial;", opts.WaypointNames)
+		assert.EqualValues(t, "false;true", opts.SnappingIncludeClosures)
+		assert.EqualValues(t, "", opts.SnappingIncludeStaticClosures)
+		assert.EqualValues(t, "distance,speed", opts.Annotations)
+		assert.EqualValues(t, "toll,ferry", opts.Exclude)
+
+		res, err := d.GetDirectionsFor(builder)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, CodeOK, res.Code)
+		assert.EqualValues(t, []string{"50;unlimited"}, lastQuery["radiuses"])
+		assert.EqualValues(t, []string{"distance,speed"}, lastQuery["annotations"])
+	})
+
+	t.Run("Rejects invalid requests without issuing them", func(t *testing.T) {
+		before := requests
+
+		_, err := d.GetDirectionsFor(NewRequestBuilder(RoutingDriving).AddLocation(start))
+		if assert.IsType(t, &CountError{}, err) {
+			assert.EqualValues(t, "coordinates", err.(*CountError).Field)
+		}
+
+		_, err = d.GetDirectionsFor(NewRequestBuilder(RoutingDriving).
+			AddLocation(start).
+			Add(RequestWaypoint{Location: end, Bearing: &Bearing{Angle: 400, Deviation: 10}}))
+		if assert.IsType(t, &ValueError{}, err) {
+			assert.EqualValues(t, "bearings", err.(*ValueError).Field)
+			assert.EqualValues(t, 1, err.(*ValueError).Index)
+		}
+
+		_, err = d.GetDirectionsFor(NewRequestBuilder(RoutingDriving).
+			Add(RequestWaypoint{Location: start, Radius: -5}).
+			AddLocation(end))
+		assert.IsType(t, &ValueError{}, err)
+
+		_, err = d.GetDirectionsFor(NewRequestBuilder(RoutingDriving).
+			AddLocation(base.Location{Latitude: -122.42, Longitude: 37.78}).
+			AddLocation(end))
+		if assert.IsType(t, &ValueError{}, err) {
+			assert.EqualValues(t, "latitude", err.(*ValueError).Field)
+		}
+
+		opts := RequestOpts{}
+		opts.SetRadiuses([]float64{10, 20, 30})
+		_, err = d.GetDirections([]base.Location{start, end}, RoutingDriving, &opts)
+		if assert.IsType(t, &CountError{}, err) {
+			assert.EqualValues(t, 2, err.(*CountError).Expected)
+			assert.EqualValues(t, 3, err.(*CountError).Actual)
+		}
+
+		assert.EqualValues(t, before, requests)
+	})
+
+	t.Run("Builds silent waypoints", func(t *testing.T) {
+		via := base.Location{Latitude: 38.8921, Longitude: -77.0431}
+
+		builder := NewRequestBuilder(RoutingDriving)
+		builder.Add(RequestWaypoint{Location: start, Name: "Start"}).
+			Add(RequestWaypoint{Location: via, Silent: true}).
+			Add(RequestWaypoint{Location: end, Name: "End"})
+
+		_, opts, err := builder.Build()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "0;2", opts.Waypoints)
+		assert.EqualValues(t, "Start;End", opts.WaypointNames)
+
+		builder.Waypoints[2].Silent = true
+		builder.Waypoints[2].Name = ""
+		_, _, err = builder.Build()
+		if assert.IsType(t, &ValueError{}, err) {
+			assert.EqualValues(t, "waypoints", err.(*ValueError).Field)
+		}
+	})
+
+	t.Run("Validates request options", func(t *testing.T) {
+		locs := []base.Location{start, end}
+
+		opts := RequestOpts{MaxHeight: 4.5, AlleyBias: -0.5, AvoidManeuverRadius: 200}
+		opts.SetDepartAt(time.Date(2019, 5, 2, 15, 0, 0, 0, time.UTC))
+		opts.SetApproaches([]ApproachType{ApproachUnrestricted, ApproachCurb})
+		_, err := d.GetDirections(locs, RoutingDrivingTraffic, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []string{"2019-05-02T15:00:00Z"}, lastQuery["depart_at"])
+		assert.EqualValues(t, []string{"4.5"}, lastQuery["max_height"])
+		assert.EqualValues(t, []string{"unrestricted;curb"}, lastQuery["approaches"])
+
+		opts.SetArriveBy(time.Now())
+		assert.IsType(t, &ValueError{}, opts.Validate(RoutingDriving, 2))
+		opts.ArriveBy = ""
+
+		opts.MaxWeight = 120
+		assert.IsType(t, &ValueError{}, opts.Validate(RoutingDriving, 2))
+		opts.MaxWeight = 0
+
+		opts.AvoidManeuverRadius = 0.5
+		assert.IsType(t, &ValueError{}, opts.Validate(RoutingDriving, 2))
+		opts.AvoidManeuverRadius = 0
+		assert.Nil(t, opts.Validate(RoutingDriving, 2))
+
+		opts.SetWaypointNames([]string{"a", "b", "c"})
+		assert.IsType(t, &CountError{}, opts.Validate(RoutingDriving, 2))
+
+		limits := RequestOpts{}
+		assert.Nil(t, limits.Validate(RoutingDriving, 4))
+		if err, ok := limits.Validate(RoutingDrivingTraffic, 4).(*CountError); assert.True(t, ok) {
+			assert.EqualValues(t, MaxCoordinatesFor(RoutingDrivingTraffic), err.Expected)
+		}
+	})
+
+	t.Run("Sets annotations", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetAnnotations([]AnnotationType{AnnotationDuration})
+		assert.EqualValues(t, "duration", opts.Annotations)
+		assert.EqualValues(t, "", opts.Radiuses)
+	})
+}
@@ -11,7 +11,9 @@ package directions
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 	"github.com/ryankurte/go-mapbox/lib/base"
@@ -22,6 +24,20 @@ const (
 	apiVersion = "v5"
 )
 
+// osrmService is the equivalent OSRM service for self hosted backends
+var osrmService = base.OSRMService{
+	Name: "route",
+	Params: []string{"alternatives", "geometries", "overview", "radiuses", "steps", "continue_straight", "bearings",
+		"annotations", "exclude", "approaches", "waypoints", "snapping"},
+}
+
+const (
+	// MinCoordinates is the minimum number of coordinates in a directions request
+	MinCoordinates = 2
+	// MaxCoordinates is the maximum number of coordinates in a directions request
+	MaxCoordinates = 25
+)
+
 // RoutingProfile defines routing mode for direction finding
 type RoutingProfile string
 
@@ -58,14 +74,44 @@ const (
 	AnnotationDuration AnnotationType = "duration"
 	AnnotationDistance AnnotationType = "distance"
 	AnnotationSpeed    AnnotationType = "speed"
+	// Congestion annotations are only available with the driving-traffic profile
+	AnnotationCongestion        AnnotationType = "congestion"
+	AnnotationCongestionNumeric AnnotationType = "congestion_numeric"
+	AnnotationMaxSpeed          AnnotationType = "maxspeed"
+	// AnnotationClosure populates RouteLeg.Closures
+	AnnotationClosure AnnotationType = "closure"
 )
 
 type RadiusType string
 
 const (
-	RaduisUnlimited RadiusType = "unlimited"
+	RadiusUnlimited RadiusType = "unlimited"
+	// Deprecated: use RadiusUnlimited
+	RaduisUnlimited = RadiusUnlimited
 )
 
+// ApproachType restricts the side of the road from which a waypoint is approached
+type ApproachType string
+
+const (
+	ApproachUnrestricted ApproachType = "unrestricted"
+	ApproachCurb         ApproachType = "curb"
+)
+
+// ExcludeType defines road types that can be excluded from routing
+type ExcludeType string
+
+const (
+	ExcludeMotorway      ExcludeType = "motorway"
+	ExcludeToll          ExcludeType = "toll"
+	ExcludeFerry         ExcludeType = "ferry"
+	ExcludeUnpaved       ExcludeType = "unpaved"
+	ExcludeCashOnlyTolls ExcludeType = "cash_only_tolls"
+)
+
+// TimeFormat is the format used for the depart_at and arrive_by query arguments
+const TimeFormat = "2006-01-02T15:04:05Z07:00"
+
 // Directions api wrapper instance
 type Directions struct {
 	base *base.Base
@@ -92,6 +138,31 @@ type RequestOpts struct {
 	VoiceInstructions  bool          `url:"voice_instructions,omitempty"`
 	BannerInstructions bool          `url:"banner_instructions,omitempty"`
 	VoiceUnits         string        `url:"voice_units,omitempty"`
+	Approaches         string        `url:"approaches,omitempty"`
+	WaypointNames      string        `url:"waypoint_names,omitempty"`
+
+	SnappingIncludeClosures       string `url:"snapping_include_closures,omitempty"`
+	SnappingIncludeStaticClosures string `url:"snapping_include_static_closures,omitempty"`
+
+	// Waypoints are the indices of coordinates that are stopping waypoints, others are silent
+	Waypoints         string `url:"waypoints,omitempty"`
+	WaypointsPerRoute bool   `url:"waypoints_per_route,omitempty"`
+
+	// DepartAt and ArriveBy are mutually exclusive, see SetDepartAt and SetArriveBy
+	DepartAt string `url:"depart_at,omitempty"`
+	ArriveBy string `url:"arrive_by,omitempty"`
+
+	// MaxHeight and MaxWidth in meters (0 to 10), MaxWeight in metric tons (0 to 100)
+	MaxHeight float64 `url:"max_height,omitempty"`
+	MaxWidth  float64 `url:"max_width,omitempty"`
+	MaxWeight float64 `url:"max_weight,omitempty"`
+
+	// AlleyBias and WalkwayBias are preferences between -1 (avoid) and 1 (prefer)
+	AlleyBias   float64 `url:"alley_bias,omitempty"`
+	WalkwayBias float64 `url:"walkway_bias,omitempty"`
+
+	// AvoidManeuverRadius is the distance in meters (1 to 300) from the start to avoid maneuvers
+	AvoidManeuverRadius float64 `url:"avoid_maneuver_radius,omitempty"`
 }
 
 // SetRadiuses sets radiuses for the maximum distance any coordinate can move when snapped to  nearby road segment.
@@ -126,12 +197,227 @@ func (o *RequestOpts) SetAnnotations(annotations []AnnotationType) {
 	for i, a := range annotations {
 		lines[i] = fmt.Sprintf("%s", a)
 	}
-	o.Radiuses = strings.Join(lines, ",")
+	o.Annotations = strings.Join(lines, ",")
+}
+
+// SetApproaches builds the approaches query argument, empty values use the API default
+// This must have the same number of approaches as locations in the GetDirections request
+func (o *RequestOpts) SetApproaches(approaches []ApproachType) {
+	lines := make([]string, len(approaches))
+	for i, a := range approaches {
+		lines[i] = string(a)
+	}
+	o.Approaches = strings.Join(lines, ";")
+}
+
+// SetWaypoints sets the indices of the coordinates that are stopping waypoints
+// This must include the first and last coordinates, other coordinates are silent waypoints
+func (o *RequestOpts) SetWaypoints(indices []int) {
+	lines := make([]string, len(indices))
+	for i, index := range indices {
+		lines[i] = strconv.Itoa(index)
+	}
+	o.Waypoints = strings.Join(lines, ";")
+}
+
+// SetWaypointNames builds the waypoint_names query argument
+// This must have the same number of names as waypoints (or locations if waypoints are not set)
+func (o *RequestOpts) SetWaypointNames(names []string) {
+	o.WaypointNames = strings.Join(names, ";")
+}
+
+// SetDepartAt sets the departure time for traffic aware routing
+func (o *RequestOpts) SetDepartAt(t time.Time) {
+	o.DepartAt = t.Format(TimeFormat)
+}
+
+// SetArriveBy sets the desired arrival time for traffic aware routing
+func (o *RequestOpts) SetArriveBy(t time.Time) {
+	o.ArriveBy = t.Format(TimeFormat)
+}
+
+// SetExclude builds the exclude query argument from an array of exclude types
+func (o *RequestOpts) SetExclude(exclude []ExcludeType) {
+	lines := make([]string, len(exclude))
+	for i, e := range exclude {
+		lines[i] = string(e)
+	}
+	o.Exclude = strings.Join(lines, ",")
+}
+
+// Validate checks the number of coordinates against the limit for the routing profile (see MaxCoordinatesFor)
+// and that per-coordinate arguments match it, returning a *CountError or *ValueError describing the first problem found
+func (o *RequestOpts) Validate(profile RoutingProfile, coordinates int) error {
+	return o.validate(coordinates, MaxCoordinatesFor(profile))
+}
+
+// validate checks the number of coordinates against a maximum (where non-zero) and the request arguments
+func (o *RequestOpts) validate(coordinates, max int) error {
+	if coordinates < MinCoordinates {
+		return &CountError{Field: "coordinates", Expected: MinCoordinates, Actual: coordinates, Min: true}
+	}
+	if max > 0 && coordinates > max {
+		return &CountError{Field: "coordinates", Expected: max, Actual: coordinates, Max: true}
+	}
+	return o.validateArguments(coordinates)
+}
+
+// validateArguments checks request arguments against the number of coordinates
+func (o *RequestOpts) validateArguments(coordinates int) error {
+	if o == nil {
+		return nil
+	}
+
+	waypoints := coordinates
+	if o.Waypoints != "" {
+		indices := strings.Split(o.Waypoints, ";")
+		last := -1
+		for i, v := range indices {
+			index, err := strconv.Atoi(v)
+			if err != nil || index <= last || index >= coordinates {
+				return &ValueError{Field: "waypoints", Index: -1, Value: o.Waypoints, Reason: "must be ascending coordinate indices"}
+			}
+			if (i == 0 && index != 0) || (i == len(indices)-1 && index != coordinates-1) {
+				return &ValueError{Field: "waypoints", Index: -1, Value: o.Waypoints, Reason: "must include the first and last coordinates"}
+			}
+			last = index
+		}
+		waypoints = len(indices)
+	}
+
+	if o.DepartAt != "" && o.ArriveBy != "" {
+		return &ValueError{Field: "arrive_by", Index: -1, Value: o.ArriveBy, Reason: "cannot be used with depart_at"}
+	}
+
+	ranges := []struct {
+		name     string
+		value    float64
+		min, max float64
+		reason   string
+	}{
+		{"max_height", o.MaxHeight, 0, 10, "must be between 0 and 10 meters"},
+		{"max_width", o.MaxWidth, 0, 10, "must be between 0 and 10 meters"},
+		{"max_weight", o.MaxWeight, 0, 100, "must be between 0 and 100 tons"},
+		{"alley_bias", o.AlleyBias, -1, 1, "must be between -1 and 1"},
+		{"walkway_bias", o.WalkwayBias, -1, 1, "must be between -1 and 1"},
+		{"avoid_maneuver_radius", o.AvoidManeuverRadius, 1, 300, "must be between 1 and 300 meters"},
+	}
+	for _, r := range ranges {
+		// Zero values are unset and omitted from the request
+		if r.value != 0 && (r.value < r.min || r.value > r.max) {
+			return &ValueError{Field: r.name, Index: -1, Value: r.value, Reason: r.reason}
+		}
+	}
+
+	fields := []struct {
+		name, value string
+		count       int
+		check       func(v string) string
+	}{
+		{"radiuses", o.Radiuses, coordinates, checkRadius},
+		{"bearings", o.Bearings, coordinates, checkBearing},
+		{"approaches", o.Approaches, coordinates, checkApproach},
+		{"waypoint_names", o.WaypointNames, waypoints, nil},
+		{"snapping_include_closures", o.SnappingIncludeClosures, coordinates, checkBool},
+		{"snapping_include_static_closures", o.SnappingIncludeStaticClosures, coordinates, checkBool},
+	}
+
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		values := strings.Split(f.value, ";")
+		if len(values) != f.count {
+			return &CountError{Field: f.name, Expected: f.count, Actual: len(values)}
+		}
+		if f.check == nil {
+			continue
+		}
+		for i, v := range values {
+			if v == "" {
+				continue
+			}
+			if reason := f.check(v); reason != "" {
+				return &ValueError{Field: f.name, Index: i, Value: v, Reason: reason}
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkRadius(v string) string {
+	if v == string(RadiusUnlimited) {
+		return ""
+	}
+	r, err := strconv.ParseFloat(v, 64)
+	if err != nil || r <= 0 {
+		return "must be greater than 0 or unlimited"
+	}
+	return ""
+}
+
+func checkBearing(v string) string {
+	parts := strings.Split(v, ",")
+	if len(parts) != 2 {
+		return "must be an angle and deviation"
+	}
+	angle, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || angle < 0 || angle > 360 {
+		return "angle must be between 0 and 360 degrees"
+	}
+	deviation, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || deviation < 0 || deviation > 180 {
+		return "deviation must be between 0 and 180 degrees"
+	}
+	return ""
+}
+
+func checkApproach(v string) string {
+	switch ApproachType(v) {
+	case ApproachUnrestricted, ApproachCurb:
+		return ""
+	}
+	return "must be unrestricted or curb"
+}
+
+func checkBool(v string) string {
+	if v != "true" && v != "false" {
+		return "must be true or false"
+	}
+	return ""
+}
+
+// validateLocations checks locations are valid coordinates
+func validateLocations(locations []base.Location) error {
+	for i, l := range locations {
+		if l.Latitude < -90 || l.Latitude > 90 {
+			return &ValueError{Field: "latitude", Index: i, Value: l.Latitude, Reason: "must be between -90 and 90 degrees"}
+		}
+		if l.Longitude < -180 || l.Longitude > 180 {
+			return &ValueError{Field: "longitude", Index: i, Value: l.Longitude, Reason: "must be between -180 and 180 degrees"}
+		}
+	}
+	return nil
 }
 
 // GetDirections between a set of locations using the specified routing profile
+// Requests are validated before being issued, see RequestOpts.Validate
 func (g *Directions) GetDirections(locations []base.Location, profile RoutingProfile, opts *RequestOpts) (*DirectionResponse, error) {
 
+	if err := validateLocations(locations); err != nil {
+		return nil, err
+	}
+
+	// Self hosted OSRM servers configure their own coordinate limits
+	max := MaxCoordinatesFor(profile)
+	if g.base.Backend() == base.BackendOSRM {
+		max = 0
+	}
+	if err := opts.validate(len(locations), max); err != nil {
+		return nil, err
+	}
+
 	v, err := query.Values(opts)
 	if err != nil {
 		return nil, err
@@ -145,7 +431,7 @@ func (g *Directions) GetDirections(locations []base.Location, profile RoutingPro
 
 	resp := DirectionResponse{}
 
-	err = g.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+	err = g.base.QueryRouting(apiName, apiVersion, osrmService, string(profile), queryString, &v, &resp)
 
 	return &resp, err
 }
@@ -0,0 +1,56 @@
+/**
+ * go-mapbox Directions Module Errors
+ * Defines errors returned when validating direction requests
+ * See https://www.mapbox.com/api-documentation/#retrieve-directions for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"fmt"
+)
+
+// CountError indicates a request argument has the wrong number of elements
+type CountError struct {
+	// Field is the query argument name
+	Field string
+	// Expected is the expected number of elements (or the limit where Min or Max are set)
+	Expected int
+	// Actual is the number of elements provided
+	Actual int
+	// Min or Max indicate Expected is a lower or upper bound
+	Min, Max bool
+}
+
+func (e *CountError) Error() string {
+	switch {
+	case e.Min:
+		return fmt.Sprintf("Directions request error: %s requires at least %d elements (received %d)", e.Field, e.Expected, e.Actual)
+	case e.Max:
+		return fmt.Sprintf("Directions request error: %s allows at most %d elements (received %d)", e.Field, e.Expected, e.Actual)
+	default:
+		return fmt.Sprintf("Directions request error: %s requires %d elements (received %d)", e.Field, e.Expected, e.Actual)
+	}
+}
+
+// ValueError indicates a request argument has an invalid value
+type ValueError struct {
+	// Field is the query argument name
+	Field string
+	// Index is the coordinate index, or -1 for request wide arguments
+	Index int
+	// Value is the invalid value
+	Value interface{}
+	// Reason describes the constraint that was violated
+	Reason string
+}
+
+func (e *ValueError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("Directions request error: invalid %s '%v' (%s)", e.Field, e.Value, e.Reason)
+	}
+	return fmt.Sprintf("Directions request error: invalid %s '%v' for coordinate %d (%s)", e.Field, e.Value, e.Index, e.Reason)
+}
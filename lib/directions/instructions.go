@@ -0,0 +1,127 @@
+/**
+ * go-mapbox Directions Module Instructions
+ * Voice and banner instruction models for turn-by-turn navigation
+ * See https://docs.mapbox.com/api/navigation/directions/#voice-instruction-object for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+// VoiceInstruction is a spoken instruction for a route step, requested with VoiceInstructions
+type VoiceInstruction struct {
+	// DistanceAlongGeometry is the distance in meters from the end of the step at which the instruction is due
+	DistanceAlongGeometry float64 `json:"distanceAlongGeometry"`
+	Announcement          string
+	SSMLAnnouncement      string `json:"ssmlAnnouncement"`
+}
+
+// BannerInstruction is a visual instruction for a route step, requested with BannerInstructions
+type BannerInstruction struct {
+	// DistanceAlongGeometry is the distance in meters from the end of the step at which the instruction is due
+	DistanceAlongGeometry float64 `json:"distanceAlongGeometry"`
+	Primary               BannerText
+	Secondary             *BannerText
+	// Sub contains lane guidance or the following maneuver
+	Sub *BannerText
+}
+
+// BannerText is a line of banner instruction text
+type BannerText struct {
+	Text     string
+	Type     string
+	Modifier StepModifier
+	// Degrees is the exit angle for roundabouts
+	Degrees     float64
+	DrivingSide string `json:"driving_side"`
+	Components  []BannerComponent
+}
+
+// BannerComponentType is the type of a banner component
+type BannerComponentType string
+
+const (
+	BannerComponentText      BannerComponentType = "text"
+	BannerComponentIcon      BannerComponentType = "icon"
+	BannerComponentDelimiter BannerComponentType = "delimiter"
+	BannerComponentExit      BannerComponentType = "exit"
+	BannerComponentExitNum   BannerComponentType = "exit-number"
+	BannerComponentLane      BannerComponentType = "lane"
+	BannerComponentGuidance  BannerComponentType = "guidance-view"
+)
+
+// BannerComponent is part of a banner instruction, such as text, a road shield or a lane
+type BannerComponent struct {
+	Text string
+	Type BannerComponentType
+	// Abbr is an abbreviation of Text, AbbrPriority orders which abbreviations are used first
+	Abbr         string
+	AbbrPriority int `json:"abbr_priority"`
+	// ImageBaseURL is the legacy shield image, MapboxShield describes a shield for rendering
+	ImageBaseURL string        `json:"imageBaseURL"`
+	MapboxShield *MapboxShield `json:"mapbox_shield"`
+	// Directions, Active and ActiveDirection describe lane components
+	Directions      []string
+	Active          bool
+	ActiveDirection string `json:"active_direction"`
+}
+
+// MapboxShield describes a route shield
+type MapboxShield struct {
+	BaseURL    string `json:"base_url"`
+	Name       string
+	TextColor  string `json:"text_color"`
+	DisplayRef string `json:"display_ref"`
+}
+
+// Lanes returns the lane components of the banner instruction, if present
+func (b *BannerInstruction) Lanes() []BannerComponent {
+	lanes := []BannerComponent{}
+	if b.Sub == nil {
+		return lanes
+	}
+	for _, c := range b.Sub.Components {
+		if c.Type == BannerComponentLane {
+			lanes = append(lanes, c)
+		}
+	}
+	return lanes
+}
+
+// VoiceInstructionAt returns the voice instruction due at the provided distance (in meters) from
+// the start of the step, or nil if no instruction is due yet
+func (s *RouteStep) VoiceInstructionAt(distance float64) *VoiceInstruction {
+	index := dueInstruction(s.Distance-distance, len(s.VoiceInstructions), func(i int) float64 {
+		return s.VoiceInstructions[i].DistanceAlongGeometry
+	})
+	if index < 0 {
+		return nil
+	}
+	return &s.VoiceInstructions[index]
+}
+
+// BannerInstructionAt returns the banner instruction due at the provided distance (in meters) from
+// the start of the step, or nil if no instruction is due yet
+func (s *RouteStep) BannerInstructionAt(distance float64) *BannerInstruction {
+	index := dueInstruction(s.Distance-distance, len(s.BannerInstructions), func(i int) float64 {
+		return s.BannerInstructions[i].DistanceAlongGeometry
+	})
+	if index < 0 {
+		return nil
+	}
+	return &s.BannerInstructions[index]
+}
+
+// dueInstruction finds the most recently triggered instruction, being the instruction with the
+// smallest distanceAlongGeometry not less than the remaining distance
+func dueInstruction(remaining float64, count int, distanceAt func(i int) float64) int {
+	index := -1
+	for i := 0; i < count; i++ {
+		d := distanceAt(i)
+		if d >= remaining && (index < 0 || d < distanceAt(index)) {
+			index = i
+		}
+	}
+	return index
+}
@@ -0,0 +1,68 @@
+/**
+ * go-mapbox Directions Module Instructions Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stepResponse = `{
+	"distance": 500,
+	"voiceInstructions": [
+		{"distanceAlongGeometry": 500, "announcement": "Head north", "ssmlAnnouncement": "<speak>Head north</speak>"},
+		{"distanceAlongGeometry": 100, "announcement": "In 100 meters, turn right"}
+	],
+	"bannerInstructions": [{
+		"distanceAlongGeometry": 500,
+		"primary": {"text": "I 95", "type": "turn", "modifier": "right", "components": [
+			{"text": "I 95", "type": "icon", "mapbox_shield": {"base_url": "https://api.mapbox.com/styles/v1/", "name": "us-interstate", "text_color": "white", "display_ref": "95"}}
+		]},
+		"sub": {"text": "", "components": [
+			{"text": "", "type": "lane", "directions": ["straight"], "active": false},
+			{"text": "", "type": "lane", "directions": ["right"], "active": true, "active_direction": "right"}
+		]}
+	}]
+}`
+
+func TestInstructions(t *testing.T) {
+
+	step := RouteStep{}
+	if err := json.Unmarshal([]byte(stepResponse), &step); !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Decodes instructions", func(t *testing.T) {
+		assert.Len(t, step.VoiceInstructions, 2)
+		assert.EqualValues(t, "<speak>Head north</speak>", step.VoiceInstructions[0].SSMLAnnouncement)
+
+		b := step.BannerInstructions[0]
+		assert.EqualValues(t, StepModifierRight, b.Primary.Modifier)
+		assert.EqualValues(t, "us-interstate", b.Primary.Components[0].MapboxShield.Name)
+		assert.Nil(t, b.Secondary)
+
+		lanes := b.Lanes()
+		if assert.Len(t, lanes, 2) {
+			assert.True(t, lanes[1].Active)
+			assert.EqualValues(t, "right", lanes[1].ActiveDirection)
+		}
+	})
+
+	t.Run("Selects the due instruction", func(t *testing.T) {
+		assert.EqualValues(t, "Head north", step.VoiceInstructionAt(0).Announcement)
+		assert.EqualValues(t, "Head north", step.VoiceInstructionAt(350).Announcement)
+		assert.EqualValues(t, "In 100 meters, turn right", step.VoiceInstructionAt(400).Announcement)
+		assert.EqualValues(t, "In 100 meters, turn right", step.VoiceInstructionAt(500).Announcement)
+		assert.NotNil(t, step.BannerInstructionAt(250))
+
+		late := RouteStep{Distance: 500, VoiceInstructions: []VoiceInstruction{{DistanceAlongGeometry: 200}}}
+		assert.Nil(t, late.VoiceInstructionAt(100))
+	})
+}
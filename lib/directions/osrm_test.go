@@ -0,0 +1,57 @@
+/**
+ * go-mapbox Directions Module OSRM Backend Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestOSRMDirections(t *testing.T) {
+
+	queries := make(chan map[string][]string, 1)
+	server := routeServer(t, queries)
+	defer server.Close()
+
+	directions := NewDirections(base.NewOSRMBase(server.URL))
+	locations := []base.Location{{Latitude: 38.9, Longitude: -77.0}, {Latitude: 38.9, Longitude: -77.01}}
+
+	t.Run("Fetches directions from OSRM", func(t *testing.T) {
+		opts := RequestOpts{Steps: true}
+		resp, err := directions.GetDirections(locations, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOK, Codes(resp.Code))
+		assert.Len(t, resp.Routes, 1)
+
+		q := <-queries
+		assert.Equal(t, []string{"true"}, q["steps"])
+	})
+
+	t.Run("Rejects Mapbox only options", func(t *testing.T) {
+		opts := RequestOpts{VoiceInstructions: true}
+		_, err := directions.GetDirections(locations, RoutingDriving, &opts)
+		assert.EqualError(t, err, "OSRM route service does not support options: voice_instructions")
+	})
+
+	t.Run("Skips Mapbox coordinate limits", func(t *testing.T) {
+		many := make([]base.Location, MaxCoordinates+5)
+		for i := range many {
+			many[i] = base.Location{Latitude: 38.9, Longitude: -77.0 - float64(i)*0.01}
+		}
+
+		resp, err := directions.GetDirections(many, RoutingDrivingTraffic, nil)
+		assert.Nil(t, err)
+		if assert.NotNil(t, resp) {
+			assert.Len(t, resp.Waypoints, len(many))
+		}
+		<-queries
+	})
+}
@@ -0,0 +1,132 @@
+/**
+ * go-mapbox Directions Module Route Refresh
+ * Wraps the mapbox directions refresh API to update traffic annotations for an existing route
+ * See https://docs.mapbox.com/api/navigation/directions/#directions-refresh-api for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"fmt"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	refreshAPIName    = "directions-refresh"
+	refreshAPIVersion = "v1"
+)
+
+// RefreshOpts request options for the directions refresh api
+type RefreshOpts struct {
+	// LegIndex is the first leg to refresh
+	LegIndex int `url:"-"`
+	// CurrentRouteGeometryIndex limits refreshed annotations to the route geometry from this index
+	CurrentRouteGeometryIndex *int `url:"current_route_geometry_index,omitempty"`
+}
+
+// RefreshResponse is the response from the directions refresh api
+type RefreshResponse struct {
+	Code  string
+	Route struct {
+		Legs []RouteLeg
+	}
+}
+
+// RefreshRoute fetches fresh annotations, incidents and closures for a route from a driving-traffic
+// response (identified by DirectionResponse.UUID and the route index) and merges them into the route.
+// The route must have been requested with annotations.
+func (g *Directions) RefreshRoute(uuid string, routeIndex int, route *Route, opts *RefreshOpts) error {
+	o := RefreshOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if uuid == "" {
+		return fmt.Errorf("Directions refresh error: a response UUID is required")
+	}
+	if o.LegIndex < 0 || o.LegIndex >= len(route.Legs) {
+		return fmt.Errorf("Directions refresh error: invalid leg index %d for route with %d legs", o.LegIndex, len(route.Legs))
+	}
+
+	// Find the offset of the current geometry index within the first refreshed leg
+	offset := 0
+	if o.CurrentRouteGeometryIndex != nil {
+		offset = *o.CurrentRouteGeometryIndex
+		for _, l := range route.Legs[:o.LegIndex] {
+			offset -= l.Annotation.segments()
+		}
+		if offset < 0 || offset > route.Legs[o.LegIndex].Annotation.segments() {
+			return fmt.Errorf("Directions refresh error: geometry index %d is not within leg %d", *o.CurrentRouteGeometryIndex, o.LegIndex)
+		}
+	}
+
+	v, err := query.Values(&o)
+	if err != nil {
+		return err
+	}
+
+	resp := RefreshResponse{}
+	queryString := fmt.Sprintf("%s/%d/%d", uuid, routeIndex, o.LegIndex)
+
+	err = g.base.Query(refreshAPIName, refreshAPIVersion, string(RoutingDrivingTraffic), queryString, &v, &resp)
+	if err != nil {
+		return err
+	}
+	if Codes(resp.Code) != CodeOK {
+		return fmt.Errorf("Directions refresh error: %s", resp.Code)
+	}
+	if o.LegIndex+len(resp.Route.Legs) > len(route.Legs) {
+		return fmt.Errorf("Directions refresh error: received %d legs for route with %d legs from leg %d", len(resp.Route.Legs), len(route.Legs), o.LegIndex)
+	}
+
+	for i, fresh := range resp.Route.Legs {
+		leg := &route.Legs[o.LegIndex+i]
+		legOffset := 0
+		if i == 0 {
+			legOffset = offset
+		}
+
+		leg.Annotation.merge(&fresh.Annotation, legOffset)
+		leg.Incidents = fresh.Incidents
+		leg.Closures = fresh.Closures
+	}
+
+	return nil
+}
+
+// segments returns the number of geometry segments covered by the annotation
+func (a *Annotation) segments() int {
+	for _, l := range []int{len(a.Distance), len(a.Duration), len(a.Speed), len(a.Congestion), len(a.CongestionNumeric), len(a.MaxSpeed)} {
+		if l > 0 {
+			return l
+		}
+	}
+	return 0
+}
+
+// merge replaces annotations from the offset segment onwards with fresh annotations
+func (a *Annotation) merge(fresh *Annotation, offset int) {
+	mergeFloats := func(dst, src []float64) []float64 {
+		if src == nil || offset > len(dst) {
+			return dst
+		}
+		return append(append([]float64{}, dst[:offset]...), src...)
+	}
+
+	a.Distance = mergeFloats(a.Distance, fresh.Distance)
+	a.Duration = mergeFloats(a.Duration, fresh.Duration)
+	a.Speed = mergeFloats(a.Speed, fresh.Speed)
+
+	if fresh.Congestion != nil && offset <= len(a.Congestion) {
+		a.Congestion = append(append([]CongestionLevel{}, a.Congestion[:offset]...), fresh.Congestion...)
+	}
+	if fresh.CongestionNumeric != nil && offset <= len(a.CongestionNumeric) {
+		a.CongestionNumeric = append(append([]*int{}, a.CongestionNumeric[:offset]...), fresh.CongestionNumeric...)
+	}
+	if fresh.MaxSpeed != nil && offset <= len(a.MaxSpeed) {
+		a.MaxSpeed = append(append([]MaxSpeed{}, a.MaxSpeed[:offset]...), fresh.MaxSpeed...)
+	}
+}
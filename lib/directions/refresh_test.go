@@ -0,0 +1,91 @@
+/**
+ * go-mapbox Directions Module Route Refresh Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const refreshResponse = `{
+	"code": "Ok",
+	"route": {"legs": [
+		{"annotation": {"duration": [20, 21], "congestion": ["heavy", "severe"]},
+		 "incidents": [{"id": "7", "type": "accident"}]},
+		{"annotation": {"duration": [30, 31, 32], "congestion": ["low", "low", "moderate"]}}
+	]}
+}`
+
+func TestRefresh(t *testing.T) {
+
+	var lastPath string
+	var lastQuery map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastPath, lastQuery = r.URL.Path, r.URL.Query()
+		w.Write([]byte(refreshResponse))
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+	d := NewDirections(b)
+
+	newRoute := func() *Route {
+		return &Route{Legs: []RouteLeg{
+			{Annotation: Annotation{Duration: []float64{1, 2, 3}, Congestion: []CongestionLevel{"low", "low", "low"}}},
+			{Annotation: Annotation{Duration: []float64{4, 5, 6, 7}, Congestion: []CongestionLevel{"low", "low", "low", "low"}}},
+			{Annotation: Annotation{Duration: []float64{8, 9, 10}, Congestion: []CongestionLevel{"low", "low", "low"}}},
+		}}
+	}
+
+	t.Run("Decodes response UUIDs", func(t *testing.T) {
+		resp := DirectionResponse{}
+		assert.Nil(t, json.Unmarshal([]byte(`{"code": "Ok", "uuid": "abc123"}`), &resp))
+		assert.EqualValues(t, "abc123", resp.UUID)
+	})
+
+	t.Run("Merges refreshed annotations", func(t *testing.T) {
+		route := newRoute()
+		index := 5
+		err := d.RefreshRoute("abc123", 1, route, &RefreshOpts{LegIndex: 1, CurrentRouteGeometryIndex: &index})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, "/directions-refresh/v1/mapbox/driving-traffic/abc123/1/1", lastPath)
+		assert.EqualValues(t, []string{"5"}, lastQuery["current_route_geometry_index"])
+
+		// Leg 0 is untouched, leg 1 is refreshed from its third segment, leg 2 is replaced
+		assert.EqualValues(t, []float64{1, 2, 3}, route.Legs[0].Annotation.Duration)
+		assert.EqualValues(t, []float64{4, 5, 20, 21}, route.Legs[1].Annotation.Duration)
+		assert.EqualValues(t, []CongestionLevel{"low", "low", CongestionHeavy, CongestionSevere}, route.Legs[1].Annotation.Congestion)
+		assert.EqualValues(t, []float64{30, 31, 32}, route.Legs[2].Annotation.Duration)
+		assert.EqualValues(t, IncidentAccident, route.Legs[1].Incidents[0].Type)
+	})
+
+	t.Run("Validates refresh requests", func(t *testing.T) {
+		assert.NotNil(t, d.RefreshRoute("", 0, newRoute(), nil))
+		assert.NotNil(t, d.RefreshRoute("abc123", 0, newRoute(), &RefreshOpts{LegIndex: 3}))
+
+		index := 1
+		assert.NotNil(t, d.RefreshRoute("abc123", 0, newRoute(), &RefreshOpts{LegIndex: 2, CurrentRouteGeometryIndex: &index}))
+
+		// Legs beyond the end of the route are rejected
+		assert.NotNil(t, d.RefreshRoute("abc123", 0, newRoute(), &RefreshOpts{LegIndex: 2}))
+	})
+}
@@ -0,0 +1,228 @@
+/**
+ * go-mapbox Directions Module Route Splitting
+ * Splits routes with more waypoints than a single request allows into overlapping requests,
+ * and stitches the results into a single response
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// DefaultSplitConcurrency is the default number of concurrent requests when splitting routes
+const DefaultSplitConcurrency = 4
+
+// ProfileMaxCoordinates are coordinate limits for profiles with a lower limit than MaxCoordinates
+// See https://docs.mapbox.com/api/navigation/directions/ for the per-profile limits
+var ProfileMaxCoordinates = map[RoutingProfile]int{
+	RoutingDrivingTraffic: 3,
+}
+
+// MaxCoordinatesFor returns the maximum number of coordinates in a request for a routing profile
+func MaxCoordinatesFor(profile RoutingProfile) int {
+	if max, ok := ProfileMaxCoordinates[profile]; ok {
+		return max
+	}
+	return MaxCoordinates
+}
+
+// SplitOpts options for splitting long routes
+type SplitOpts struct {
+	// MaxCoordinates overrides the coordinate limit for each request (see MaxCoordinatesFor)
+	MaxCoordinates int
+	// Concurrency is the maximum number of concurrent requests
+	Concurrency int
+}
+
+// SplitLocations splits locations into chunks of at most max locations, where each chunk starts
+// with the last location of the previous chunk
+func SplitLocations(locations []base.Location, max int) [][]base.Location {
+	ranges := splitRanges(len(locations), max)
+	chunks := make([][]base.Location, len(ranges))
+	for i, r := range ranges {
+		chunks[i] = locations[r[0]:r[1]]
+	}
+	return chunks
+}
+
+// splitRanges calculates the [start, end) coordinate index ranges of overlapping chunks
+func splitRanges(count, max int) [][2]int {
+	if max < MinCoordinates {
+		max = MinCoordinates
+	}
+	ranges := [][2]int{}
+	for start := 0; start < count-1; start += max - 1 {
+		end := start + max
+		if end > count {
+			end = count
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// GetDirectionsSplit fetches directions for any number of locations by splitting them into
+// overlapping requests within the profile limit, fetched concurrently, and stitching the results
+// into a single response with one route.
+// Per-coordinate arguments are split with the locations, silent waypoints are not supported,
+// and all requests use the same depart_at or arrive_by time.
+func (g *Directions) GetDirectionsSplit(locations []base.Location, profile RoutingProfile, opts *RequestOpts, split *SplitOpts) (*DirectionResponse, error) {
+	s := SplitOpts{}
+	if split != nil {
+		s = *split
+	}
+	if s.MaxCoordinates <= 0 {
+		s.MaxCoordinates = MaxCoordinatesFor(profile)
+	}
+	if s.Concurrency <= 0 {
+		s.Concurrency = DefaultSplitConcurrency
+	}
+
+	o := RequestOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if len(locations) < MinCoordinates {
+		return nil, &CountError{Field: "coordinates", Expected: MinCoordinates, Actual: len(locations), Min: true}
+	}
+	if err := validateLocations(locations); err != nil {
+		return nil, err
+	}
+	if err := o.validateArguments(len(locations)); err != nil {
+		return nil, err
+	}
+	if o.Waypoints != "" {
+		return nil, &ValueError{Field: "waypoints", Index: -1, Value: o.Waypoints, Reason: "silent waypoints are not supported when splitting routes"}
+	}
+	o.Alternatives = false
+
+	ranges := splitRanges(len(locations), s.MaxCoordinates)
+	responses := make([]*DirectionResponse, len(ranges))
+	errs := make([]error, len(ranges))
+
+	wg := sync.WaitGroup{}
+	limit := make(chan struct{}, s.Concurrency)
+
+	for i, r := range ranges {
+		wg.Add(1)
+		go func(i int, r [2]int) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			chunkOpts := o.slice(r[0], r[1])
+			responses[i], errs[i] = g.GetDirections(locations[r[0]:r[1]], profile, &chunkOpts)
+			if errs[i] == nil && Codes(responses[i].Code) != CodeOK {
+				errs[i] = fmt.Errorf("Directions error: %s", responses[i].Code)
+			}
+			if errs[i] == nil && len(responses[i].Routes) == 0 {
+				errs[i] = fmt.Errorf("Directions error: no route returned")
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching locations %d to %d: %s", ranges[i][0], ranges[i][1]-1, err)
+		}
+	}
+
+	geometry := GeometryPolyline
+	if o.Geometries != nil {
+		geometry = *o.Geometries
+	}
+
+	return stitchResponses(responses, geometry)
+}
+
+// slice returns a copy of the options with per-coordinate arguments limited to [start, end)
+func (o RequestOpts) slice(start, end int) RequestOpts {
+	sliceField := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return strings.Join(strings.Split(value, ";")[start:end], ";")
+	}
+
+	o.Radiuses = sliceField(o.Radiuses)
+	o.Bearings = sliceField(o.Bearings)
+	o.Approaches = sliceField(o.Approaches)
+	o.WaypointNames = sliceField(o.WaypointNames)
+	o.SnappingIncludeClosures = sliceField(o.SnappingIncludeClosures)
+	o.SnappingIncludeStaticClosures = sliceField(o.SnappingIncludeStaticClosures)
+
+	return o
+}
+
+// stitchResponses merges responses for consecutive overlapping chunks into a single response
+func stitchResponses(responses []*DirectionResponse, geometry GeometryType) (*DirectionResponse, error) {
+	first := responses[0]
+	route := first.Routes[0]
+	route.Legs = append([]RouteLeg{}, route.Legs...)
+	route.Waypoints = append([]Waypoint{}, route.Waypoints...)
+
+	stitched := &DirectionResponse{
+		Code:      first.Code,
+		Waypoints: append([]Waypoint{}, first.Waypoints...),
+	}
+
+	var path []base.Location
+	if route.Geometry != "" {
+		var err error
+		if path, err = decodePath(route.Geometry, geometry); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, resp := range responses[1:] {
+		r := resp.Routes[0]
+
+		route.Distance += r.Distance
+		route.Duration += r.Duration
+		route.Weight += r.Weight
+		route.Legs = append(route.Legs, r.Legs...)
+
+		// Skip the overlapping waypoint at the start of each following chunk
+		if len(resp.Waypoints) > 0 {
+			stitched.Waypoints = append(stitched.Waypoints, resp.Waypoints[1:]...)
+		}
+		if len(r.Waypoints) > 0 {
+			route.Waypoints = append(route.Waypoints, r.Waypoints[1:]...)
+		}
+
+		if path != nil {
+			p, err := decodePath(r.Geometry, geometry)
+			if err != nil {
+				return nil, err
+			}
+			if len(p) > 0 {
+				path = append(path, p[1:]...)
+			}
+		}
+	}
+
+	if path != nil {
+		precision := base.PolylinePrecision5
+		if geometry == GeometryPolyline6 {
+			precision = base.PolylinePrecision6
+		}
+		route.Geometry = base.EncodePolyline(path, precision)
+	}
+	if len(route.Waypoints) == 0 {
+		route.Waypoints = nil
+	}
+
+	stitched.Routes = []Route{route}
+
+	return stitched, nil
+}
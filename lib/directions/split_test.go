@@ -0,0 +1,130 @@
+/**
+ * go-mapbox Directions Module Route Splitting Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// routeServer returns a straight line route through the requested coordinates with one leg per pair
+func routeServer(t *testing.T, queries chan map[string][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if queries != nil {
+			queries <- r.URL.Query()
+		}
+
+		locations := []base.Location{}
+		for _, c := range strings.Split(path.Base(r.URL.Path), ";") {
+			parts := strings.Split(c, ",")
+			lng, _ := strconv.ParseFloat(parts[0], 64)
+			lat, _ := strconv.ParseFloat(parts[1], 64)
+			locations = append(locations, base.Location{Latitude: lat, Longitude: lng})
+		}
+
+		resp := DirectionResponse{Code: string(CodeOK)}
+		route := Route{Geometry: base.EncodePolyline(locations, base.PolylinePrecision5)}
+		for i, l := range locations {
+			resp.Waypoints = append(resp.Waypoints, Waypoint{Name: strconv.Itoa(i), Location: []float64{l.Longitude, l.Latitude}})
+			if i > 0 {
+				route.Legs = append(route.Legs, RouteLeg{Distance: 100, Duration: 10})
+				route.Distance += 100
+				route.Duration += 10
+			}
+		}
+		resp.Routes = []Route{route}
+
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func TestSplit(t *testing.T) {
+
+	locations := make([]base.Location, 60)
+	for i := range locations {
+		locations[i] = base.Location{Latitude: 38.9, Longitude: -77 + float64(i)*0.001}
+	}
+
+	t.Run("Splits locations into overlapping chunks", func(t *testing.T) {
+		chunks := SplitLocations(locations, 25)
+		if assert.Len(t, chunks, 3) {
+			assert.Len(t, chunks[0], 25)
+			assert.Len(t, chunks[1], 25)
+			assert.Len(t, chunks[2], 12)
+			assert.EqualValues(t, chunks[0][24], chunks[1][0])
+		}
+
+		assert.Len(t, SplitLocations(locations[:3], MaxCoordinatesFor(RoutingDrivingTraffic)), 1)
+		assert.Len(t, SplitLocations(locations[:4], MaxCoordinatesFor(RoutingDrivingTraffic)), 2)
+	})
+
+	t.Run("Fetches and stitches split routes", func(t *testing.T) {
+		queries := make(chan map[string][]string, 10)
+		server := routeServer(t, queries)
+		defer server.Close()
+
+		b, err := base.NewBase("test-token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.SetBaseURL(server.URL)
+		d := NewDirections(b)
+
+		radiuses := make([]float64, len(locations))
+		for i := range radiuses {
+			radiuses[i] = float64(i + 1)
+		}
+		opts := RequestOpts{}
+		opts.SetRadiuses(radiuses)
+
+		resp, err := d.GetDirectionsSplit(locations, RoutingDriving, &opts, nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		close(queries)
+
+		// Each chunk receives its own radiuses
+		counts := []int{}
+		for q := range queries {
+			counts = append(counts, len(strings.Split(q["radiuses"][0], ";")))
+		}
+		assert.ElementsMatch(t, []int{25, 25, 12}, counts)
+
+		assert.Len(t, resp.Waypoints, 60)
+		if assert.Len(t, resp.Routes, 1) {
+			r := resp.Routes[0]
+			assert.Len(t, r.Legs, 59)
+			assert.EqualValues(t, 5900, r.Distance)
+			assert.EqualValues(t, 590, r.Duration)
+
+			path, err := r.GetPath(GeometryPolyline)
+			if assert.Nil(t, err) && assert.Len(t, path, 60) {
+				assert.InDelta(t, locations[59].Longitude, path[59].Longitude, 1e-5)
+			}
+		}
+	})
+
+	t.Run("Rejects silent waypoints", func(t *testing.T) {
+		d := NewDirections(&base.Base{})
+		opts := RequestOpts{}
+		opts.SetWaypoints([]int{0, 59})
+		_, err := d.GetDirectionsSplit(locations, RoutingDriving, &opts, nil)
+		assert.IsType(t, &ValueError{}, err)
+	})
+}
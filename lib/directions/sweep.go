@@ -0,0 +1,194 @@
+/**
+ * go-mapbox Directions Module Departure Sweep
+ * Queries traffic aware directions across a window of departure times to find the best time to leave
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	// DefaultSweepConcurrency is the default number of concurrent sweep requests
+	DefaultSweepConcurrency = 4
+	// DefaultSweepRate is the default maximum sweep request rate in requests per second
+	DefaultSweepRate = 5.0
+	// MaxSweepSamples is the maximum number of departure times in a sweep
+	MaxSweepSamples = 1000
+	// sweepRetries is the number of retries for rate limited sweep requests
+	sweepRetries = 3
+	// sweepBackoff is the initial delay before retrying a rate limited sweep request
+	sweepBackoff = time.Second
+)
+
+// DepartAtMaxFuture is how far in the future the API accepts depart_at times
+var DepartAtMaxFuture = 183 * 24 * time.Hour
+
+// SweepOpts options for departure time sweeps
+type SweepOpts struct {
+	// Start and End are the first and last departure times, which must be within the allowed future window
+	Start, End time.Time
+	// Step is the interval between departure times
+	Step time.Duration
+	// Concurrency is the maximum number of concurrent requests
+	Concurrency int
+	// Rate is the maximum request rate in requests per second
+	Rate float64
+}
+
+// DepartureSample is the result of directions for a single departure time
+type DepartureSample struct {
+	DepartAt time.Time
+	// Duration and Distance of the fastest route
+	Duration time.Duration
+	Distance float64
+	Route    *Route
+	// Err is set if the request for this departure time failed
+	Err error
+}
+
+// SweepResult is the result of a departure time sweep
+type SweepResult struct {
+	// Samples are the results for each departure time in order
+	Samples []DepartureSample
+	// Best is the successful sample with the shortest duration, nil if all requests failed
+	Best *DepartureSample
+}
+
+// SweepDepartures fetches driving-traffic directions for departure times across a window,
+// returning the travel time for each departure and the departure with the shortest travel time.
+// The depart_at and arrive_by options are overwritten for each request.
+func (g *Directions) SweepDepartures(locations []base.Location, opts *RequestOpts, sweep *SweepOpts) (*SweepResult, error) {
+	return g.SweepDeparturesContext(context.Background(), locations, opts, sweep)
+}
+
+// SweepDeparturesContext runs a SweepDepartures that stops issuing requests when the provided context is cancelled,
+// returning the partial result with the context error. Samples that were not requested have Err set to the context error.
+func (g *Directions) SweepDeparturesContext(ctx context.Context, locations []base.Location, opts *RequestOpts, sweep *SweepOpts) (*SweepResult, error) {
+	if sweep == nil {
+		return nil, fmt.Errorf("Departure sweep error: sweep options are required")
+	}
+	s := *sweep
+	if s.Concurrency <= 0 {
+		s.Concurrency = DefaultSweepConcurrency
+	}
+	if s.Rate <= 0 {
+		s.Rate = DefaultSweepRate
+	}
+
+	now := time.Now()
+	switch {
+	case s.Step <= 0:
+		return nil, fmt.Errorf("Departure sweep error: step must be greater than zero")
+	case s.End.Before(s.Start):
+		return nil, fmt.Errorf("Departure sweep error: end must not be before start")
+	case s.Start.Before(now.Add(-time.Minute)):
+		return nil, fmt.Errorf("Departure sweep error: start must not be in the past")
+	case s.End.After(now.Add(DepartAtMaxFuture)):
+		return nil, fmt.Errorf("Departure sweep error: end must be within %s of now", DepartAtMaxFuture)
+	case s.End.Sub(s.Start)/s.Step >= MaxSweepSamples:
+		return nil, fmt.Errorf("Departure sweep error: at most %d departure times are allowed, increase the step", MaxSweepSamples)
+	}
+
+	o := RequestOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	o.ArriveBy = ""
+
+	if err := validateLocations(locations); err != nil {
+		return nil, err
+	}
+	// Check against the driving-traffic coordinate limit before any requests are scheduled
+	if err := o.Validate(RoutingDrivingTraffic, len(locations)); err != nil {
+		return nil, err
+	}
+
+	result := &SweepResult{}
+	for t := s.Start; !t.After(s.End); t = t.Add(s.Step) {
+		result.Samples = append(result.Samples, DepartureSample{DepartAt: t})
+	}
+
+	jobs := make(chan int)
+	done := make(chan struct{})
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / s.Rate))
+	defer ticker.Stop()
+
+	for i := 0; i < s.Concurrency; i++ {
+		go func() {
+			for index := range jobs {
+				sample := &result.Samples[index]
+				if ctx.Err() != nil {
+					sample.Err = ctx.Err()
+					continue
+				}
+
+				requestOpts := o
+				requestOpts.SetDepartAt(sample.DepartAt)
+
+				var resp *DirectionResponse
+				var err error
+				backoff := sweepBackoff
+				for attempt := 0; ; attempt++ {
+					select {
+					case <-ticker.C:
+					case <-ctx.Done():
+					}
+					if err = ctx.Err(); err != nil {
+						break
+					}
+
+					resp, err = g.GetDirections(locations, RoutingDrivingTraffic, &requestOpts)
+					if err != base.ErrorAPILimitExceeded || attempt == sweepRetries {
+						break
+					}
+
+					select {
+					case <-time.After(backoff):
+					case <-ctx.Done():
+					}
+					backoff *= 2
+				}
+
+				switch {
+				case err != nil:
+					sample.Err = err
+				case Codes(resp.Code) != CodeOK:
+					sample.Err = fmt.Errorf("Directions error: %s", resp.Code)
+				case len(resp.Routes) == 0:
+					sample.Err = fmt.Errorf("Directions error: no route returned")
+				default:
+					sample.Route = &resp.Routes[0]
+					sample.Duration = time.Duration(sample.Route.Duration * float64(time.Second))
+					sample.Distance = sample.Route.Distance
+				}
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	for i := range result.Samples {
+		jobs <- i
+	}
+	close(jobs)
+	for i := 0; i < s.Concurrency; i++ {
+		<-done
+	}
+
+	for i := range result.Samples {
+		sample := &result.Samples[i]
+		if sample.Err == nil && (result.Best == nil || sample.Duration < result.Best.Duration) {
+			result.Best = sample
+		}
+	}
+
+	return result, ctx.Err()
+}
@@ -0,0 +1,128 @@
+/**
+ * go-mapbox Directions Module Departure Sweep Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestSweep(t *testing.T) {
+
+	start := time.Now().Add(time.Hour).Truncate(time.Hour).UTC()
+
+	var requests int32
+
+	// Travel time is shortest for departures two hours after the start
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !strings.Contains(r.URL.Path, string(RoutingDrivingTraffic)) {
+			http.Error(w, "unexpected profile", http.StatusBadRequest)
+			return
+		}
+		departAt, err := time.Parse(TimeFormat, r.URL.Query().Get("depart_at"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		duration := 1200 + 60*math.Abs(departAt.Sub(start).Hours()-2)
+		fmt.Fprintf(w, `{"code": "Ok", "routes": [{"distance": 20000, "duration": %f}]}`, duration)
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+	d := NewDirections(b)
+
+	locs := []base.Location{{Latitude: 38.8893, Longitude: -77.0502}, {Latitude: 38.8977, Longitude: -77.0365}}
+
+	t.Run("Finds the best departure time", func(t *testing.T) {
+		res, err := d.SweepDepartures(locs, nil, &SweepOpts{Start: start, End: start.Add(4 * time.Hour), Step: 30 * time.Minute, Rate: 1000})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		if assert.Len(t, res.Samples, 9) {
+			assert.EqualValues(t, start, res.Samples[0].DepartAt)
+			assert.EqualValues(t, 22*time.Minute, res.Samples[0].Duration)
+			for _, s := range res.Samples {
+				assert.Nil(t, s.Err)
+			}
+		}
+		if assert.NotNil(t, res.Best) {
+			assert.EqualValues(t, start.Add(2*time.Hour), res.Best.DepartAt)
+			assert.EqualValues(t, 20*time.Minute, res.Best.Duration)
+		}
+	})
+
+	t.Run("Validates the departure window", func(t *testing.T) {
+		_, err := d.SweepDepartures(locs, nil, &SweepOpts{Start: start.Add(-3 * time.Hour), End: start, Step: time.Hour})
+		assert.NotNil(t, err)
+		_, err = d.SweepDepartures(locs, nil, &SweepOpts{Start: start, End: start.Add(DepartAtMaxFuture + time.Hour), Step: time.Hour})
+		assert.NotNil(t, err)
+		_, err = d.SweepDepartures(locs, nil, &SweepOpts{Start: start, End: start.Add(time.Hour)})
+		assert.NotNil(t, err)
+	})
+	t.Run("Validates the driving-traffic coordinate limit", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		many := make([]base.Location, MaxCoordinatesFor(RoutingDrivingTraffic)+1)
+		for i := range many {
+			many[i] = base.Location{Latitude: 38.89, Longitude: -77.05 + float64(i)*0.01}
+		}
+
+		_, err := d.SweepDepartures(many, nil, &SweepOpts{Start: start, End: start.Add(time.Hour), Step: time.Hour, Rate: 1000})
+		assert.IsType(t, &CountError{}, err)
+		assert.EqualValues(t, 0, atomic.LoadInt32(&requests))
+	})
+	t.Run("Limits the number of departure times", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		_, err := d.SweepDepartures(locs, nil, &SweepOpts{Start: start, End: start.Add(24 * time.Hour), Step: time.Nanosecond})
+		assert.NotNil(t, err)
+		_, err = d.SweepDepartures(locs, nil, &SweepOpts{Start: start, End: start.Add(MaxSweepSamples * time.Minute), Step: time.Minute})
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, atomic.LoadInt32(&requests))
+
+		res, err := d.SweepDepartures(locs, nil, &SweepOpts{Start: start, End: start.Add((MaxSweepSamples - 1) * time.Second), Step: time.Second, Rate: 1e6})
+		assert.Nil(t, err)
+		assert.Len(t, res.Samples, MaxSweepSamples)
+	})
+
+	t.Run("Stops when cancelled", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res, err := d.SweepDeparturesContext(ctx, locs, nil, &SweepOpts{Start: start, End: start.Add(4 * time.Hour), Step: 30 * time.Minute})
+		assert.Equal(t, context.Canceled, err)
+		if assert.NotNil(t, res) {
+			assert.Len(t, res.Samples, 9)
+			for _, s := range res.Samples {
+				assert.Equal(t, context.Canceled, s.Err)
+			}
+			assert.Nil(t, res.Best)
+		}
+		assert.EqualValues(t, 0, atomic.LoadInt32(&requests))
+	})
+}
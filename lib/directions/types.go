@@ -9,21 +9,51 @@
 
 package directions
 
+import (
+	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
 // DirectionResponse is the response from GetDirections
 // https://www.mapbox.com/api-documentation/#directions-response-object
 type DirectionResponse struct {
 	Code      string
 	Waypoints []Waypoint
 	Routes    []Route
+	// UUID identifies the response for refreshing routes, see RefreshRoute
+	UUID string `json:"uuid"`
 }
 
 // Route A route through (potentially multiple) waypoints.
 // https://www.mapbox.com/api-documentation/#route-object
 type Route struct {
-	Distance float64
-	Duration float64
-	Geometry string
-	Legs     []RouteLeg
+	Distance    float64
+	Duration    float64
+	Geometry    string
+	Weight      float64
+	WeightName  string `json:"weight_name"`
+	VoiceLocale string `json:"voiceLocale"`
+	Legs        []RouteLeg
+	// Waypoints are only populated when requested with WaypointsPerRoute
+	Waypoints []Waypoint
+}
+
+// GetPath decodes the route geometry into a path using the geometry type the route was requested with
+// Only polyline geometries are currently supported
+func (r *Route) GetPath(geometry GeometryType) ([]base.Location, error) {
+	return decodePath(r.Geometry, geometry)
+}
+
+func decodePath(encoded string, geometry GeometryType) ([]base.Location, error) {
+	switch geometry {
+	case "", GeometryPolyline:
+		return base.DecodePolyline(encoded, base.PolylinePrecision5)
+	case GeometryPolyline6:
+		return base.DecodePolyline(encoded, base.PolylinePrecision6)
+	default:
+		return nil, fmt.Errorf("Unsupported geometry type: %s", geometry)
+	}
 }
 
 // Waypoint is an input point snapped to the road network
@@ -31,26 +61,121 @@ type Route struct {
 type Waypoint struct {
 	Name     string
 	Location []float64
+	// Distance is the distance in meters the input coordinate was moved when snapped
+	Distance float64
 }
 
 // RouteLeg A route between two Waypoints
 // https://www.mapbox.com/api-documentation/#routeleg-object
 type RouteLeg struct {
-	Distance   float64
-	Duration   float64
-	Steps      []RouteStep
-	Summary    string
-	Annotation Annotation
+	Distance     float64
+	Duration     float64
+	Weight       float64
+	Steps        []RouteStep
+	Summary      string
+	Annotation   Annotation
+	Incidents    []Incident
+	Admins       []Admin
+	ViaWaypoints []ViaWaypoint `json:"via_waypoints"`
+	// Closures are populated when requested with AnnotationClosure
+	Closures []Closure
 }
 
 // Annotation conains additional details about each line segment
 // https://www.mapbox.com/api-documentation/#routeleg-object
 type Annotation struct {
-	Distance []float64
-	Duration []float64
-	Speed    []float64
+	Distance   []float64
+	Duration   []float64
+	Speed      []float64
+	Congestion []CongestionLevel
+	// CongestionNumeric is congestion from 0 (free flow) to 100 (heavy), nil where unknown
+	CongestionNumeric []*int     `json:"congestion_numeric"`
+	MaxSpeed          []MaxSpeed `json:"maxspeed"`
+}
+
+// CongestionLevel is the level of congestion on a line segment
+type CongestionLevel string
+
+const (
+	CongestionUnknown  CongestionLevel = "unknown"
+	CongestionLow      CongestionLevel = "low"
+	CongestionModerate CongestionLevel = "moderate"
+	CongestionHeavy    CongestionLevel = "heavy"
+	CongestionSevere   CongestionLevel = "severe"
+)
+
+// MaxSpeed is the posted speed limit of a line segment
+type MaxSpeed struct {
+	Speed float64
+	// Unit is km/h or mph
+	Unit string
+	// Unknown indicates the speed limit is not known
+	Unknown bool
+	// None indicates there is no speed limit
+	None bool
+}
+
+// Closure is a closed section of a route leg, by geometry index
+type Closure struct {
+	GeometryIndexStart int `json:"geometry_index_start"`
+	GeometryIndexEnd   int `json:"geometry_index_end"`
 }
 
+// Admin is an administrative region a route leg passes through
+type Admin struct {
+	ISO3166_1       string `json:"iso_3166_1"`
+	ISO3166_1Alpha3 string `json:"iso_3166_1_alpha3"`
+}
+
+// ViaWaypoint is a silent waypoint along a route leg
+type ViaWaypoint struct {
+	WaypointIndex     int     `json:"waypoint_index"`
+	DistanceFromStart float64 `json:"distance_from_start"`
+	GeometryIndex     int     `json:"geometry_index"`
+}
+
+// Incident is a traffic incident along a route leg
+type Incident struct {
+	ID                 string
+	Type               IncidentType
+	Description        string
+	LongDescription    string `json:"long_description"`
+	CreationTime       string `json:"creation_time"`
+	StartTime          string `json:"start_time"`
+	EndTime            string `json:"end_time"`
+	Impact             string
+	SubType            string `json:"sub_type"`
+	SubTypeDescription string `json:"sub_type_description"`
+	AlertcCodes        []int  `json:"alertc_codes"`
+	Closed             bool
+	LanesBlocked       []string `json:"lanes_blocked"`
+	AffectedRoadNames  []string `json:"affected_road_names"`
+	Congestion         struct {
+		Value int
+	}
+	GeometryIndexStart int    `json:"geometry_index_start"`
+	GeometryIndexEnd   int    `json:"geometry_index_end"`
+	CountryCode        string `json:"iso_3166_1_alpha2"`
+}
+
+// IncidentType is the type of a traffic incident
+type IncidentType string
+
+const (
+	IncidentAccident        IncidentType = "accident"
+	IncidentCongestion      IncidentType = "congestion"
+	IncidentConstruction    IncidentType = "construction"
+	IncidentDisabledVehicle IncidentType = "disabled_vehicle"
+	IncidentLaneRestriction IncidentType = "lane_restriction"
+	IncidentMassTransit     IncidentType = "mass_transit"
+	IncidentMiscellaneous   IncidentType = "miscellaneous"
+	IncidentOtherNews       IncidentType = "other_news"
+	IncidentPlannedEvent    IncidentType = "planned_event"
+	IncidentRoadClosure     IncidentType = "road_closure"
+	IncidentRoadHazard      IncidentType = "road_hazard"
+	IncidentWeather         IncidentType = "weather"
+)
+
 // RouteStep Includes one StepManeuver object and travel to the following RouteStep.
 // https://www.mapbox.com/api-documentation/#routestep-object
 type RouteStep struct {
@@ -63,6 +188,14 @@ type RouteStep struct {
 	Mode          TransportationMode
 	Maneuver      StepManeuver
 	Intersections []Intersection
+	// VoiceInstructions and BannerInstructions are populated when requested, see instructions.go
+	VoiceInstructions  []VoiceInstruction  `json:"voiceInstructions"`
+	BannerInstructions []BannerInstruction `json:"bannerInstructions"`
+}
+
+// GetPath decodes the step geometry into a path using the geometry type the route was requested with
+func (s *RouteStep) GetPath(geometry GeometryType) ([]base.Location, error) {
+	return decodePath(s.Geometry, geometry)
 }
 
 // TransportationMode indicates the mode of transportation
@@ -86,8 +219,19 @@ type Intersection struct {
 	In       uint
 	Out      uint
 	Lanes    []Lane
+	// Classes of the road exiting the intersection (eg. toll, ferry, motorway)
+	Classes []string
 }
 
+// Road classes for Intersection.Classes
+const (
+	ClassToll       = "toll"
+	ClassFerry      = "ferry"
+	ClassRestricted = "restricted"
+	ClassMotorway   = "motorway"
+	ClassTunnel     = "tunnel"
+)
+
 // Lane
 //https://www.mapbox.com/api-documentation/#lane-object
 type Lane struct {
@@ -99,13 +243,36 @@ type Lane struct {
 // https://www.mapbox.com/api-documentation/#stepmaneuver-object
 type StepManeuver struct {
 	Location      []float64
-	BearingBefore float64
-	BearingAfter  float64
+	BearingBefore float64 `json:"bearing_before"`
+	BearingAfter  float64 `json:"bearing_after"`
 	Instruction   string
 	Type          string
 	Modifier      StepModifier
+	// Exit is the exit number for roundabout and rotary maneuvers
+	Exit int
 }
 
+// Maneuver types for StepManeuver.Type
+// https://www.mapbox.com/api-documentation/#maneuver-types
+const (
+	ManeuverTurn           = "turn"
+	ManeuverNewName        = "new name"
+	ManeuverDepart         = "depart"
+	ManeuverArrive         = "arrive"
+	ManeuverMerge          = "merge"
+	ManeuverOnRamp         = "on ramp"
+	ManeuverOffRamp        = "off ramp"
+	ManeuverFork           = "fork"
+	ManeuverEndOfRoad      = "end of road"
+	ManeuverContinue       = "continue"
+	ManeuverRoundabout     = "roundabout"
+	ManeuverRotary         = "rotary"
+	ManeuverRoundaboutTurn = "roundabout turn"
+	ManeuverNotification   = "notification"
+	ManeuverExitRoundabout = "exit roundabout"
+	ManeuverExitRotary     = "exit rotary"
+)
+
 // StepModifier indicates the direction change of the maneuver
 // https://www.mapbox.com/api-documentation/#stepmaneuver-object
 type StepModifier string
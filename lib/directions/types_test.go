@@ -0,0 +1,67 @@
+/**
+ * go-mapbox Directions Module Type Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const routeResponse = `{
+	"code": "Ok",
+	"routes": [{
+		"distance": 1200.5, "duration": 300.2, "weight": 320.1, "weight_name": "auto", "voiceLocale": "en-US",
+		"waypoints": [{"name": "Start", "location": [-77.0502, 38.8893], "distance": 3.2}],
+		"legs": [{
+			"distance": 1200.5, "duration": 300.2,
+			"annotation": {
+				"congestion": ["low", "severe"],
+				"congestion_numeric": [10, null],
+				"maxspeed": [{"speed": 50, "unit": "km/h"}, {"unknown": true}]
+			},
+			"incidents": [{"id": "1", "type": "construction", "closed": true, "congestion": {"value": 80},
+				"geometry_index_start": 2, "geometry_index_end": 5, "iso_3166_1_alpha2": "US"}],
+			"admins": [{"iso_3166_1": "US", "iso_3166_1_alpha3": "USA"}],
+			"via_waypoints": [{"waypoint_index": 1, "distance_from_start": 640.2, "geometry_index": 3}],
+			"closures": [{"geometry_index_start": 2, "geometry_index_end": 4}]
+		}]
+	}]
+}`
+
+func TestTypes(t *testing.T) {
+
+	t.Run("Decodes extended route fields", func(t *testing.T) {
+		resp := DirectionResponse{}
+		if err := json.Unmarshal([]byte(routeResponse), &resp); !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		r := resp.Routes[0]
+		assert.EqualValues(t, "auto", r.WeightName)
+		assert.EqualValues(t, "en-US", r.VoiceLocale)
+		assert.EqualValues(t, 3.2, r.Waypoints[0].Distance)
+
+		l := r.Legs[0]
+		assert.EqualValues(t, []CongestionLevel{CongestionLow, CongestionSevere}, l.Annotation.Congestion)
+		if assert.Len(t, l.Annotation.CongestionNumeric, 2) {
+			assert.EqualValues(t, 10, *l.Annotation.CongestionNumeric[0])
+			assert.Nil(t, l.Annotation.CongestionNumeric[1])
+		}
+		assert.EqualValues(t, MaxSpeed{Speed: 50, Unit: "km/h"}, l.Annotation.MaxSpeed[0])
+		assert.True(t, l.Annotation.MaxSpeed[1].Unknown)
+
+		assert.EqualValues(t, IncidentConstruction, l.Incidents[0].Type)
+		assert.EqualValues(t, 80, l.Incidents[0].Congestion.Value)
+		assert.EqualValues(t, "US", l.Incidents[0].CountryCode)
+		assert.EqualValues(t, "USA", l.Admins[0].ISO3166_1Alpha3)
+		assert.EqualValues(t, ViaWaypoint{WaypointIndex: 1, DistanceFromStart: 640.2, GeometryIndex: 3}, l.ViaWaypoints[0])
+		assert.EqualValues(t, Closure{GeometryIndexStart: 2, GeometryIndexEnd: 4}, l.Closures[0])
+	})
+}
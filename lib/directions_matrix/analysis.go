@@ -0,0 +1,241 @@
+/**
+ * go-mapbox Directions Matrix Module Analysis
+ * Provides ranking, assignment, clustering and reachability helpers over matrices
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directionsmatrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultClusterIterations is the default maximum number of k-medoids iterations
+const DefaultClusterIterations = 100
+
+// Neighbour is a destination and its duration or distance from a source
+type Neighbour struct {
+	Index int
+	Value float64
+}
+
+// Rank returns the reachable destinations from a source ordered from nearest to furthest,
+// with ties ordered by destination index
+func (m Matrix) Rank(source int) []Neighbour {
+	neighbours := []Neighbour{}
+	for j, v := range m[source] {
+		if !IsUnreachable(v) {
+			neighbours = append(neighbours, Neighbour{j, v})
+		}
+	}
+	sort.SliceStable(neighbours, func(a, b int) bool {
+		return neighbours[a].Value < neighbours[b].Value
+	})
+	return neighbours
+}
+
+// NearestK returns up to k of the nearest reachable destinations from a source
+func (m Matrix) NearestK(source, k int) []Neighbour {
+	neighbours := m.Rank(source)
+	if k < len(neighbours) {
+		neighbours = neighbours[:k]
+	}
+	return neighbours
+}
+
+// Reachable returns the destinations reachable from a source within a budget (eg. a time limit in seconds)
+func (m Matrix) Reachable(source int, budget float64) []int {
+	reachable := []int{}
+	for j, v := range m[source] {
+		if v <= budget {
+			reachable = append(reachable, j)
+		}
+	}
+	return reachable
+}
+
+// Assign assigns each source (eg. a customer) to its nearest destination (eg. a depot), returning the
+// destination index for each source or -1 where a source can reach no destination with remaining capacity.
+// Capacities limits the number of sources assigned to each destination, unlimited if nil.
+// With capacities, assignment is greedy in order of increasing cost and so may not be optimal.
+func (m Matrix) Assign(capacities []int) ([]int, error) {
+	assignments := make([]int, len(m))
+	for i := range assignments {
+		assignments[i] = -1
+	}
+
+	if capacities == nil {
+		for i := range m {
+			if nearest := m.NearestK(i, 1); len(nearest) > 0 {
+				assignments[i] = nearest[0].Index
+			}
+		}
+		return assignments, nil
+	}
+
+	remaining := append([]int{}, capacities...)
+	pairs := [][2]int{}
+	for i, row := range m {
+		if len(row) != len(capacities) {
+			return nil, fmt.Errorf("Matrix error: %d capacities provided for %d destinations", len(capacities), len(row))
+		}
+		for j, v := range row {
+			if !IsUnreachable(v) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool {
+		return m[pairs[a][0]][pairs[a][1]] < m[pairs[b][0]][pairs[b][1]]
+	})
+
+	for _, p := range pairs {
+		if assignments[p[0]] < 0 && remaining[p[1]] > 0 {
+			assignments[p[0]] = p[1]
+			remaining[p[1]]--
+		}
+	}
+
+	return assignments, nil
+}
+
+// Clustering is the result of k-medoids clustering
+type Clustering struct {
+	// Medoids are the indices of the locations at the center of each cluster
+	Medoids []int
+	// Clusters are the cluster index for each location, or -1 where a location is unreachable from all medoids
+	Clusters []int
+	// Cost is the total duration or distance from each reachable location's medoid to the location
+	Cost float64
+}
+
+// KMedoids clusters the locations of a square matrix (with the same sources and destinations) into k clusters,
+// each centered on one of the locations (the medoid). Costs are measured from the medoid to each member, as for
+// depots serving customers. Medoids are initialised deterministically, starting from the most central location
+// then adding the location furthest from the existing medoids, and refined for up to iterations rounds.
+func (m Matrix) KMedoids(k, iterations int) (*Clustering, error) {
+	n := len(m)
+	for _, row := range m {
+		if len(row) != n {
+			return nil, fmt.Errorf("Matrix error: k-medoids clustering requires a square matrix")
+		}
+	}
+	if k <= 0 || k > n {
+		return nil, fmt.Errorf("Matrix error: k must be between 1 and %d", n)
+	}
+	if iterations <= 0 {
+		iterations = DefaultClusterIterations
+	}
+
+	// Start with the location with the lowest total cost to all others
+	medoids := []int{}
+	best, bestCost := 0, math.Inf(1)
+	for i := 0; i < n; i++ {
+		if c := m.cost(i, allIndices(n)); c < bestCost {
+			best, bestCost = i, c
+		}
+	}
+	medoids = append(medoids, best)
+
+	// Add the location furthest from the existing medoids, preferring unreachable locations
+	for len(medoids) < k {
+		next, nextCost := -1, -1.0
+		for i := 0; i < n; i++ {
+			if contains(medoids, i) {
+				continue
+			}
+			c := math.Inf(1)
+			for _, md := range medoids {
+				c = math.Min(c, m[md][i])
+			}
+			if c > nextCost {
+				next, nextCost = i, c
+			}
+		}
+		medoids = append(medoids, next)
+	}
+
+	clusters := m.assignMedoids(medoids)
+	for iter := 0; iter < iterations; iter++ {
+		changed := false
+
+		// Move each medoid to the member minimising the cost to the other members
+		for c := range medoids {
+			members := []int{}
+			for i, cluster := range clusters {
+				if cluster == c {
+					members = append(members, i)
+				}
+			}
+			current := m.cost(medoids[c], members)
+			for _, candidate := range members {
+				if cost := m.cost(candidate, members); cost < current {
+					medoids[c], current, changed = candidate, cost, true
+				}
+			}
+		}
+
+		if !changed {
+			break
+		}
+		clusters = m.assignMedoids(medoids)
+	}
+
+	clustering := &Clustering{Medoids: medoids, Clusters: clusters}
+	for i, c := range clusters {
+		if c >= 0 {
+			clustering.Cost += m[medoids[c]][i]
+		}
+	}
+
+	return clustering, nil
+}
+
+// assignMedoids assigns each location to the cluster of the nearest medoid
+func (m Matrix) assignMedoids(medoids []int) []int {
+	clusters := make([]int, len(m))
+	for i := range clusters {
+		clusters[i] = -1
+		best := Unreachable
+		for c, md := range medoids {
+			if md == i {
+				clusters[i] = c
+				break
+			}
+			if v := m[md][i]; v < best {
+				clusters[i], best = c, v
+			}
+		}
+	}
+	return clusters
+}
+
+// cost sums the values from a location to a set of locations
+func (m Matrix) cost(from int, to []int) float64 {
+	total := 0.0
+	for _, j := range to {
+		total += m[from][j]
+	}
+	return total
+}
+
+func allIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+/**
+ * go-mapbox Directions Matrix Module Analysis Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directionsmatrix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalysis(t *testing.T) {
+
+	u := Unreachable
+
+	// Customers (sources) to depots (destinations)
+	depots := Matrix{
+		{10, 40, u},
+		{20, 15, u},
+		{30, 25, 5},
+		{u, u, u},
+	}
+
+	t.Run("Ranks reachable destinations", func(t *testing.T) {
+		assert.Equal(t, []Neighbour{{2, 5}, {1, 25}, {0, 30}}, depots.Rank(2))
+		assert.Equal(t, []Neighbour{{0, 10}}, depots.NearestK(0, 1))
+		assert.Equal(t, []Neighbour{{1, 15}, {0, 20}}, depots.NearestK(1, 5))
+		assert.Empty(t, depots.NearestK(3, 2))
+	})
+
+	t.Run("Finds destinations within a budget", func(t *testing.T) {
+		assert.Equal(t, []int{0, 1}, depots.Reachable(1, 20))
+		assert.Equal(t, []int{}, depots.Reachable(3, math.MaxFloat64))
+	})
+
+	t.Run("Assigns sources to the nearest destination", func(t *testing.T) {
+		assignments, err := depots.Assign(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0, 1, 2, -1}, assignments)
+
+		assignments, err = depots.Assign([]int{2, 0, 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0, 0, 2, -1}, assignments)
+
+		assignments, err = depots.Assign([]int{1, 1, 0})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0, 1, -1, -1}, assignments)
+
+		_, err = depots.Assign([]int{1})
+		assert.NotNil(t, err)
+	})
+
+	// Two groups of three nearby locations and an isolated location
+	locations := Matrix{
+		{0, 2, 3, 50, 52, 51, u},
+		{2, 0, 2, 50, 50, 50, u},
+		{3, 2, 0, 49, 50, 50, u},
+		{50, 50, 49, 0, 1, 2, u},
+		{52, 50, 50, 1, 0, 1, u},
+		{51, 50, 50, 2, 1, 0, u},
+		{u, u, u, u, u, u, 0},
+	}
+
+	t.Run("Clusters locations by travel time", func(t *testing.T) {
+		c, err := locations[:6].trim(6).KMedoids(2, 0)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.ElementsMatch(t, []int{1, 4}, c.Medoids)
+		assert.Equal(t, c.Clusters[0], c.Clusters[1])
+		assert.Equal(t, c.Clusters[0], c.Clusters[2])
+		assert.Equal(t, c.Clusters[3], c.Clusters[4])
+		assert.Equal(t, c.Clusters[3], c.Clusters[5])
+		assert.NotEqual(t, c.Clusters[0], c.Clusters[3])
+		assert.Equal(t, 6.0, c.Cost)
+	})
+
+	t.Run("Handles unreachable locations when clustering", func(t *testing.T) {
+		c, err := locations.KMedoids(2, 0)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Contains(t, c.Medoids, 6)
+		assert.Equal(t, c.Clusters[6], indexOf(c.Medoids, 6))
+
+		c, err = locations.KMedoids(1, 0)
+		assert.Nil(t, err)
+		assert.Contains(t, c.Clusters, -1)
+		assert.False(t, IsUnreachable(c.Cost))
+	})
+
+	t.Run("Validates clustering arguments", func(t *testing.T) {
+		_, err := depots.KMedoids(1, 0)
+		assert.NotNil(t, err)
+		_, err = locations.KMedoids(8, 0)
+		assert.NotNil(t, err)
+		_, err = locations.KMedoids(0, 0)
+		assert.NotNil(t, err)
+	})
+}
+
+// trim limits each row of a matrix to the first n columns
+func (m Matrix) trim(n int) Matrix {
+	out := make(Matrix, len(m))
+	for i, row := range m {
+		out[i] = row[:n]
+	}
+	return out
+}
+
+func indexOf(values []int, value int) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -11,7 +11,9 @@ package directionsmatrix
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 	"github.com/ryankurte/go-mapbox/lib/base"
@@ -22,6 +24,12 @@ const (
 	apiVersion = "v1"
 )
 
+// osrmService is the equivalent OSRM service for self hosted backends
+var osrmService = base.OSRMService{
+	Name:   "table",
+	Params: []string{"sources", "destinations", "annotations", "fallback_speed", "radiuses", "bearings", "approaches"},
+}
+
 // DirectionsMatrix api wrapper instance
 type DirectionsMatrix struct {
 	base *base.Base
@@ -36,6 +44,8 @@ func NewDirectionsMatrix(base *base.Base) *DirectionsMatrix {
 type RoutingProfile string
 
 const (
+	// RoutingDrivingTraffic mode for automotive routing takes into account current and historic traffic
+	RoutingDrivingTraffic RoutingProfile = "mapbox/driving-traffic"
 	// RoutingDriving mode for for automovide routing
 	RoutingDriving RoutingProfile = "mapbox/driving"
 	// RoutingWalking mode for Pedestrian routing
@@ -44,70 +54,195 @@ const (
 	RoutingCycling RoutingProfile = "mapbox/cycling"
 )
 
-// DirectionMatrixResponse is the response from GetDirections
-// https://www.mapbox.com/api-documentation/#matrix-response-format
-type DirectionMatrixResponse struct {
-	Code         string
-	Durations    [][]float64
-	Sources      []Waypoint
-	Destinations []Waypoint
-}
+// MinCoordinates is the minimum number of coordinates in a matrix request
+const MinCoordinates = 2
 
-// Waypoint is an input point snapped to the road network
-// https://www.mapbox.com/api-documentation/#waypoint-object
-type Waypoint struct {
-	Name     string
-	Location []float64
-}
+// TimeFormat is the format of depart_at times
+const TimeFormat = "2006-01-02T15:04:05Z07:00"
+
+// AnnotationType type of values to be returned in the matrix
+type AnnotationType string
+
+const (
+	// AnnotationDuration returns travel times in seconds
+	AnnotationDuration AnnotationType = "duration"
+	// AnnotationDistance returns travel distances in meters
+	AnnotationDistance AnnotationType = "distance"
+)
 
-// Codes are direction response Codes
-// https://www.mapbox.com/api-documentation/#matrix-errors
-type Codes string
+// ApproachType restricts the side of the road from which a coordinate is approached
+type ApproachType string
 
 const (
-	// CodeOK success response
-	CodeOK Codes = "Ok"
-	//CodeProfileNotFound invalid routing profile
-	CodeProfileNotFound Codes = "ProfileNotFound"
-	// CodeInvalidInput invalid input data to the server
-	CodeInvalidInput Codes = "InvalidInput"
+	// ApproachUnrestricted allows either side of the road
+	ApproachUnrestricted ApproachType = "unrestricted"
+	// ApproachCurb requires the coordinate be approached from the curb side (the side of the road the vehicle drives on)
+	ApproachCurb ApproachType = "curb"
 )
 
 // RequestOpts request options for directions api
 type RequestOpts struct {
-	Sources      string `url:"sources,omitempty"`
-	Destinations string `url:"destinations,omitempty"`
+	// Sources and Destinations are coordinate indices, all coordinates are used where these are empty
+	Sources      []int `url:"sources,semicolon,omitempty"`
+	Destinations []int `url:"destinations,semicolon,omitempty"`
+
+	Annotations string `url:"annotations,omitempty"`
+	Approaches  string `url:"approaches,omitempty"`
+	Bearings    string `url:"bearings,omitempty"`
+
+	// FallbackSpeed in kilometers per hour estimates unreachable cells from the straight line distance
+	FallbackSpeed float64 `url:"fallback_speed,omitempty"`
+
+	// DepartAt is the departure time for traffic aware matrices, see SetDepartAt
+	DepartAt string `url:"depart_at,omitempty"`
 }
 
-// SetSources The points which will act as the starting point.
-func (o *RequestOpts) SetSources(sources []string) {
-	if sources[0] == "all" {
-		o.Sources = "all"
-	} else {
-		lines := make([]string, len(sources))
-		for i, r := range sources {
-			lines[i] = fmt.Sprintf("%s", r)
-		}
-		o.Sources = strings.Join(lines, ";")
+// SetSources The points which will act as the starting point, all points are used if empty.
+func (o *RequestOpts) SetSources(sources []int) {
+	o.Sources = sources
+}
+
+// SetDestinations The points which will act as the destinations, all points are used if empty.
+func (o *RequestOpts) SetDestinations(destinations []int) {
+	o.Destinations = destinations
+}
+
+// SetAnnotations builds the annotations query argument from an array of annotation types
+func (o *RequestOpts) SetAnnotations(annotations []AnnotationType) {
+	lines := make([]string, len(annotations))
+	for i, a := range annotations {
+		lines[i] = string(a)
+	}
+	o.Annotations = strings.Join(lines, ",")
+}
+
+// SetApproaches builds the approaches query argument, empty values use the API default
+// This must have the same number of approaches as locations in the GetDirectionsMatrix request
+func (o *RequestOpts) SetApproaches(approaches []ApproachType) {
+	lines := make([]string, len(approaches))
+	for i, a := range approaches {
+		lines[i] = string(a)
+	}
+	o.Approaches = strings.Join(lines, ";")
+}
+
+// SetBearings builds the bearings query argument from an array of angles and deviations
+// This must have the same number of bearings as locations in the GetDirectionsMatrix request
+func (o *RequestOpts) SetBearings(angles []float64, deviations []float64) error {
+	if len(angles) != len(deviations) {
+		return fmt.Errorf("RequestOpts.SetBearings error, angle and deviation arrays must have the same length")
+	}
+
+	lines := make([]string, len(angles))
+	for i := range angles {
+		lines[i] = fmt.Sprintf("%f,%f", angles[i], deviations[i])
 	}
+	o.Bearings = strings.Join(lines, ";")
+
+	return nil
 }
 
-// SetDestinations The points which will act as the destinations.
-func (o *RequestOpts) SetDestinations(destinations []string) {
-	if destinations[0] == "all" {
-		o.Destinations = "all"
-	} else {
-		lines := make([]string, len(destinations))
-		for i, r := range destinations {
-			lines[i] = fmt.Sprintf("%s", r)
+// SetDepartAt sets the departure time for traffic aware matrices
+func (o *RequestOpts) SetDepartAt(t time.Time) {
+	o.DepartAt = t.Format(TimeFormat)
+}
+
+// Validate checks request arguments against the number of coordinates
+func (o *RequestOpts) Validate(coordinates int) error {
+	if o == nil {
+		return nil
+	}
+
+	indices := []struct {
+		name   string
+		values []int
+	}{
+		{"sources", o.Sources},
+		{"destinations", o.Destinations},
+	}
+	for _, f := range indices {
+		for _, index := range f.values {
+			if index < 0 || index >= coordinates {
+				return fmt.Errorf("Directions matrix request error: %s index %d out of range for %d coordinates", f.name, index, coordinates)
+			}
+		}
+	}
+
+	if o.Annotations != "" {
+		for _, a := range strings.Split(o.Annotations, ",") {
+			if AnnotationType(a) != AnnotationDuration && AnnotationType(a) != AnnotationDistance {
+				return fmt.Errorf("Directions matrix request error: invalid annotation '%s' (must be duration or distance)", a)
+			}
+		}
+	}
+
+	if o.FallbackSpeed < 0 {
+		return fmt.Errorf("Directions matrix request error: fallback_speed must be greater than 0")
+	}
+
+	if o.DepartAt != "" {
+		if _, err := time.Parse(TimeFormat, o.DepartAt); err != nil {
+			return fmt.Errorf("Directions matrix request error: invalid depart_at '%s'", o.DepartAt)
+		}
+	}
+
+	fields := []struct {
+		name, value string
+		check       func(v string) bool
+	}{
+		{"approaches", o.Approaches, checkApproach},
+		{"bearings", o.Bearings, checkBearing},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
 		}
-		o.Destinations = strings.Join(lines, ";")
+		values := strings.Split(f.value, ";")
+		if len(values) != coordinates {
+			return fmt.Errorf("Directions matrix request error: %s requires %d elements (received %d)", f.name, coordinates, len(values))
+		}
+		for i, v := range values {
+			if v != "" && !f.check(v) {
+				return fmt.Errorf("Directions matrix request error: invalid %s '%s' for coordinate %d", f.name, v, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkApproach(v string) bool {
+	return ApproachType(v) == ApproachUnrestricted || ApproachType(v) == ApproachCurb
+}
+
+func checkBearing(v string) bool {
+	parts := strings.Split(v, ",")
+	if len(parts) != 2 {
+		return false
+	}
+	angle, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || angle < 0 || angle > 360 {
+		return false
 	}
+	deviation, err := strconv.ParseFloat(parts[1], 64)
+	return err == nil && deviation >= 0 && deviation <= 180
 }
 
 // GetDirectionsMatrix between a set of locations using the specified routing profile
+// Requests are validated before being issued, see RequestOpts.Validate
 func (d *DirectionsMatrix) GetDirectionsMatrix(locations []base.Location, profile RoutingProfile, opts *RequestOpts) (*DirectionMatrixResponse, error) {
 
+	// Self hosted OSRM servers configure their own coordinate limits
+	if len(locations) < MinCoordinates {
+		return nil, fmt.Errorf("Directions matrix request error: requires at least %d coordinates (received %d)", MinCoordinates, len(locations))
+	}
+	if max := MaxCoordinatesFor(profile); d.base.Backend() != base.BackendOSRM && len(locations) > max {
+		return nil, fmt.Errorf("Directions matrix request error: requires %d to %d coordinates (received %d)", MinCoordinates, max, len(locations))
+	}
+	if err := opts.Validate(len(locations)); err != nil {
+		return nil, err
+	}
+
 	v, err := query.Values(opts)
 	if err != nil {
 		return nil, err
@@ -121,7 +256,7 @@ func (d *DirectionsMatrix) GetDirectionsMatrix(locations []base.Location, profil
 
 	resp := DirectionMatrixResponse{}
 
-	err = d.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+	err = d.base.QueryRouting(apiName, apiVersion, osrmService, string(profile), queryString, &v, &resp)
 
 	return &resp, err
 }
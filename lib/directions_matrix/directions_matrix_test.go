@@ -30,10 +30,8 @@ func TestDirectionsMatrix(t *testing.T) {
 
 	t.Run("Can Lookup Directions Matrix", func(t *testing.T) {
 		var opts RequestOpts
-		source := []string{"0", "1"}
-		dest := []string{"all"}
-		opts.SetSources(source)
-		opts.SetDestinations(dest)
+		opts.SetSources([]int{0, 1})
+		opts.SetAnnotations([]AnnotationType{AnnotationDuration, AnnotationDistance})
 
 		locs := []base.Location{{37.752759, -122.467600}, {37.762819, -122.460304}, {37.758095, -122.442253}}
 
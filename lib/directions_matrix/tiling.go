@@ -0,0 +1,356 @@
+/**
+ * go-mapbox Directions Matrix Module Tiling
+ * Computes matrices larger than a single request allows by tiling them into sub-requests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directionsmatrix
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	// MaxCoordinates is the maximum number of coordinates in a matrix request
+	MaxCoordinates = 25
+	// DefaultTileConcurrency is the default number of concurrent tile requests
+	DefaultTileConcurrency = 4
+	// DefaultTileRate is the default maximum tile request rate in requests per second (the API allows 60 per minute)
+	DefaultTileRate = 1.0
+	// DefaultTileRetries is the default number of retries for failed tile requests
+	DefaultTileRetries = 3
+	// tileBackoff is the initial delay before retrying a failed tile request
+	tileBackoff = time.Second
+)
+
+// ProfileMaxCoordinates are coordinate limits for profiles with a lower limit than MaxCoordinates
+var ProfileMaxCoordinates = map[RoutingProfile]int{
+	RoutingDrivingTraffic: 10,
+}
+
+// MaxCoordinatesFor returns the maximum number of coordinates in a request for a routing profile
+func MaxCoordinatesFor(profile RoutingProfile) int {
+	if max, ok := ProfileMaxCoordinates[profile]; ok {
+		return max
+	}
+	return MaxCoordinates
+}
+
+// TileOpts options for tiled matrix requests
+type TileOpts struct {
+	// MaxCoordinates overrides the coordinate limit for each request (see MaxCoordinatesFor)
+	MaxCoordinates int
+	// Concurrency is the maximum number of concurrent requests
+	Concurrency int
+	// Rate is the maximum request rate in requests per second
+	Rate float64
+	// Retries is the number of retries for each failed request, a negative value disables retries
+	Retries int
+	// Progress is called with the number of completed and total requests as each request completes
+	Progress func(done, total int)
+}
+
+// TileFailure is a block of a tiled matrix that could not be fetched
+type TileFailure struct {
+	// Sources and Destinations are the [start, end) index ranges of the block
+	Sources, Destinations [2]int
+	// Err is the error returned by the last attempt to fetch the block
+	Err error
+}
+
+// TileError is returned with the partial result of a tiled matrix request when some tiles could not be fetched
+type TileError struct {
+	// Failed are the failed tiles ordered by source then destination
+	Failed []TileFailure
+	// Tiles is the total number of tiles requested
+	Tiles int
+}
+
+func (e *TileError) Error() string {
+	f := e.Failed[0]
+	return fmt.Sprintf("Error fetching %d of %d tiles, sources %d to %d, destinations %d to %d: %s", len(e.Failed), e.Tiles,
+		f.Sources[0], f.Sources[1]-1, f.Destinations[0], f.Destinations[1]-1, f.Err)
+}
+
+// Contains checks whether the cell for a source and destination is in a failed tile, distinguishing failed
+// cells from unreachable cells in the partial result
+func (e *TileError) Contains(source, destination int) bool {
+	for _, f := range e.Failed {
+		if source >= f.Sources[0] && source < f.Sources[1] && destination >= f.Destinations[0] && destination < f.Destinations[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// tile is a block of the matrix covering [srcStart, srcEnd) sources and [dstStart, dstEnd) destinations
+type tile struct {
+	srcStart, srcEnd int
+	dstStart, dstEnd int
+}
+
+type tileResult struct {
+	tile tile
+	resp *DirectionMatrixResponse
+	err  error
+}
+
+// tileSizes calculates the number of sources and destinations in each tile so each request is within the limit
+func tileSizes(sources, destinations, max int) (int, int) {
+	if sources+destinations <= max {
+		return sources, destinations
+	}
+	srcSize := sources
+	if srcSize > max/2 {
+		srcSize = max / 2
+	}
+	dstSize := destinations
+	if dstSize > max-srcSize {
+		dstSize = max - srcSize
+	}
+	if sources > max-dstSize {
+		srcSize = max - dstSize
+	} else {
+		srcSize = sources
+	}
+	return srcSize, dstSize
+}
+
+// tiles splits an N x M matrix into tiles
+func tiles(sources, destinations, max int) []tile {
+	srcSize, dstSize := tileSizes(sources, destinations, max)
+	tiles := []tile{}
+	for s := 0; s < sources; s += srcSize {
+		for d := 0; d < destinations; d += dstSize {
+			t := tile{s, s + srcSize, d, d + dstSize}
+			if t.srcEnd > sources {
+				t.srcEnd = sources
+			}
+			if t.dstEnd > destinations {
+				t.dstEnd = destinations
+			}
+			tiles = append(tiles, t)
+		}
+	}
+	return tiles
+}
+
+// GetDirectionsMatrixTiled computes the matrix from every source to every destination for any number of
+// locations, by splitting the matrix into tiles within the request limit, fetched concurrently under a rate limit.
+// Each tile request contains the tile sources followed by the tile destinations, and the sources and destinations
+// options are overwritten for each request. Per-coordinate approaches and bearings are not supported.
+// If any tile fails the partial result is returned with a *TileError listing the failed tiles,
+// with the cells of failed tiles set to Unreachable.
+func (d *DirectionsMatrix) GetDirectionsMatrixTiled(sources, destinations []base.Location, profile RoutingProfile, opts *RequestOpts, tileOpts *TileOpts) (*DirectionMatrixResponse, error) {
+	t := TileOpts{}
+	if tileOpts != nil {
+		t = *tileOpts
+	}
+	if t.MaxCoordinates <= 0 {
+		t.MaxCoordinates = MaxCoordinatesFor(profile)
+	}
+	if t.Concurrency <= 0 {
+		t.Concurrency = DefaultTileConcurrency
+	}
+	if t.Rate <= 0 {
+		t.Rate = DefaultTileRate
+	}
+	if t.Retries < 0 {
+		t.Retries = 0
+	} else if t.Retries == 0 {
+		t.Retries = DefaultTileRetries
+	}
+
+	if len(sources) == 0 || len(destinations) == 0 {
+		return nil, fmt.Errorf("Directions matrix error: sources and destinations are required")
+	}
+	if t.MaxCoordinates < 2 {
+		return nil, fmt.Errorf("Directions matrix error: at least 2 coordinates are required per request")
+	}
+
+	o := RequestOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Approaches != "" || o.Bearings != "" {
+		return nil, fmt.Errorf("Directions matrix error: approaches and bearings are not supported for tiled requests")
+	}
+	o.Sources, o.Destinations = nil, nil
+	if err := o.Validate(MinCoordinates); err != nil {
+		return nil, err
+	}
+
+	result := &DirectionMatrixResponse{
+		Code:         string(CodeOK),
+		Sources:      make([]Waypoint, len(sources)),
+		Destinations: make([]Waypoint, len(destinations)),
+	}
+
+	pending := tiles(len(sources), len(destinations), t.MaxCoordinates)
+	jobs := make(chan tile)
+	results := make(chan tileResult)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / t.Rate))
+	defer ticker.Stop()
+
+	for i := 0; i < t.Concurrency; i++ {
+		go func() {
+			for tl := range jobs {
+				resp, err := d.getTile(sources, destinations, profile, o, tl, t.Retries, ticker.C)
+				results <- tileResult{tl, resp, err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, tl := range pending {
+			jobs <- tl
+		}
+		close(jobs)
+	}()
+
+	failed := []TileFailure{}
+	for done := 1; done <= len(pending); done++ {
+		r := <-results
+		if r.err != nil {
+			failed = append(failed, TileFailure{
+				Sources:      [2]int{r.tile.srcStart, r.tile.srcEnd},
+				Destinations: [2]int{r.tile.dstStart, r.tile.dstEnd},
+				Err:          r.err,
+			})
+		} else {
+			mergeTile(result, r.tile, r.resp)
+		}
+
+		if t.Progress != nil {
+			t.Progress(done, len(pending))
+		}
+	}
+	if len(failed) == 0 {
+		return result, nil
+	}
+
+	sort.Slice(failed, func(a, b int) bool {
+		fa, fb := failed[a], failed[b]
+		return fa.Sources[0] < fb.Sources[0] || (fa.Sources[0] == fb.Sources[0] && fa.Destinations[0] < fb.Destinations[0])
+	})
+	for _, f := range failed {
+		tl := tile{f.Sources[0], f.Sources[1], f.Destinations[0], f.Destinations[1]}
+		fillMatrix(result.Durations, tl, Unreachable)
+		fillMatrix(result.Distances, tl, Unreachable)
+	}
+
+	return result, &TileError{Failed: failed, Tiles: len(pending)}
+}
+
+// retryable checks whether a failed request may succeed if retried, rate limited requests,
+// server errors and transport failures are retried while invalid requests are not
+func retryable(err error) bool {
+	if err == base.ErrorAPILimitExceeded || err == base.ErrorAPIServer {
+		return true
+	}
+	_, ok := err.(*url.Error)
+	return ok
+}
+
+// getTile fetches a single tile, retrying failed requests (see retryable) with an increasing delay
+func (d *DirectionsMatrix) getTile(sources, destinations []base.Location, profile RoutingProfile, o RequestOpts, t tile, retries int, ticks <-chan time.Time) (*DirectionMatrixResponse, error) {
+	srcCount, dstCount := t.srcEnd-t.srcStart, t.dstEnd-t.dstStart
+
+	locations := make([]base.Location, 0, srcCount+dstCount)
+	locations = append(locations, sources[t.srcStart:t.srcEnd]...)
+	locations = append(locations, destinations[t.dstStart:t.dstEnd]...)
+
+	o.Sources, o.Destinations = make([]int, srcCount), make([]int, dstCount)
+	for i := range o.Sources {
+		o.Sources[i] = i
+	}
+	for i := range o.Destinations {
+		o.Destinations[i] = srcCount + i
+	}
+
+	var resp *DirectionMatrixResponse
+	var err error
+	backoff := tileBackoff
+	for attempt := 0; ; attempt++ {
+		<-ticks
+
+		resp, err = d.GetDirectionsMatrix(locations, profile, &o)
+		if err == nil || !retryable(err) || attempt == retries {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	switch {
+	case err != nil:
+		return nil, err
+	case Codes(resp.Code) != CodeOK:
+		return nil, fmt.Errorf("Directions matrix error: %s", resp.Code)
+	case len(resp.Sources) != srcCount || len(resp.Destinations) != dstCount ||
+		!matrixSize(resp.Durations, srcCount, dstCount) || !matrixSize(resp.Distances, srcCount, dstCount):
+		return nil, fmt.Errorf("Directions matrix error: response does not match requested sources and destinations")
+	}
+
+	return resp, nil
+}
+
+// matrixSize checks a matrix is absent or has the expected size
+func matrixSize(m Matrix, rows, cols int) bool {
+	if m == nil {
+		return true
+	}
+	if len(m) != rows {
+		return false
+	}
+	for _, row := range m {
+		if len(row) != cols {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeTile copies a tile response into the full matrix
+func mergeTile(result *DirectionMatrixResponse, t tile, resp *DirectionMatrixResponse) {
+	mergeMatrix(&result.Durations, resp.Durations, t, len(result.Sources), len(result.Destinations))
+	mergeMatrix(&result.Distances, resp.Distances, t, len(result.Sources), len(result.Destinations))
+	copy(result.Sources[t.srcStart:t.srcEnd], resp.Sources)
+	copy(result.Destinations[t.dstStart:t.dstEnd], resp.Destinations)
+}
+
+// mergeMatrix copies a tile matrix into the full matrix, allocating it on first use
+func mergeMatrix(dst *Matrix, src Matrix, t tile, rows, cols int) {
+	if src == nil {
+		return
+	}
+	if *dst == nil {
+		*dst = make(Matrix, rows)
+		for i := range *dst {
+			(*dst)[i] = make([]float64, cols)
+		}
+	}
+	for i, row := range src {
+		copy((*dst)[t.srcStart+i][t.dstStart:t.dstEnd], row)
+	}
+}
+
+// fillMatrix sets the cells of a tile in an allocated matrix to a value
+func fillMatrix(m Matrix, t tile, v float64) {
+	if m == nil {
+		return
+	}
+	for i := t.srcStart; i < t.srcEnd; i++ {
+		for j := t.dstStart; j < t.dstEnd; j++ {
+			m[i][j] = v
+		}
+	}
+}
@@ -0,0 +1,236 @@
+/**
+ * go-mapbox Directions Matrix Module Tiling Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directionsmatrix
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// matrixServer returns durations of the absolute difference between location longitudes (in thousandths),
+// failing requests with the status returned by fail for the request number where it is non-zero
+func matrixServer(t *testing.T, requests *int32, fail func(n int32, r *http.Request) int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status := fail(atomic.AddInt32(requests, 1), r); status != 0 {
+			http.Error(w, `{"message": "Request failed"}`, status)
+			return
+		}
+
+		locations := []float64{}
+		for _, c := range strings.Split(path.Base(r.URL.Path), ";") {
+			lng, _ := strconv.ParseFloat(strings.Split(c, ",")[0], 64)
+			locations = append(locations, lng)
+		}
+		if len(locations) > MaxCoordinates {
+			http.Error(w, `{"message": "Too many coordinates"}`, http.StatusBadRequest)
+			return
+		}
+
+		indices := func(param string) []int {
+			out := []int{}
+			for _, s := range strings.Split(r.URL.Query().Get(param), ";") {
+				i, _ := strconv.Atoi(s)
+				out = append(out, i)
+			}
+			return out
+		}
+
+		resp := DirectionMatrixResponse{Code: string(CodeOK)}
+		for _, s := range indices("sources") {
+			resp.Sources = append(resp.Sources, Waypoint{Name: strconv.Itoa(s), Location: []float64{locations[s], 0}})
+			row := []float64{}
+			for _, d := range indices("destinations") {
+				row = append(row, math.Round(math.Abs(locations[s]-locations[d])*1000))
+			}
+			resp.Durations = append(resp.Durations, row)
+		}
+		for _, d := range indices("destinations") {
+			resp.Destinations = append(resp.Destinations, Waypoint{Location: []float64{locations[d], 0}})
+		}
+
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+// failFirst fails the first count requests with a status
+func failFirst(count int32, status int) func(n int32, r *http.Request) int {
+	return func(n int32, r *http.Request) int {
+		if n <= count {
+			return status
+		}
+		return 0
+	}
+}
+
+func TestTiling(t *testing.T) {
+
+	locations := func(n int) []base.Location {
+		locs := make([]base.Location, n)
+		for i := range locs {
+			locs[i] = base.Location{Latitude: 0, Longitude: float64(i) * 0.001}
+		}
+		return locs
+	}
+
+	t.Run("Tiles within the coordinate limit", func(t *testing.T) {
+		for _, c := range [][2]int{{200, 200}, {3, 200}, {200, 3}, {10, 10}, {1, 1}} {
+			tiles := tiles(c[0], c[1], MaxCoordinates)
+			covered := 0
+			for _, tl := range tiles {
+				assert.True(t, tl.srcEnd-tl.srcStart+tl.dstEnd-tl.dstStart <= MaxCoordinates)
+				covered += (tl.srcEnd - tl.srcStart) * (tl.dstEnd - tl.dstStart)
+			}
+			assert.Equal(t, c[0]*c[1], covered)
+		}
+		assert.Len(t, tiles(10, 10, MaxCoordinates), 1)
+		assert.Len(t, tiles(200, 200, MaxCoordinates), 17*16)
+	})
+
+	t.Run("Computes and assembles large matrices", func(t *testing.T) {
+		var requests int32
+		server := matrixServer(t, &requests, failFirst(1, http.StatusTooManyRequests))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+		d := NewDirectionsMatrix(b)
+
+		sources, destinations := locations(60), locations(40)
+		progress := []int{}
+
+		res, err := d.GetDirectionsMatrixTiled(sources, destinations, RoutingDriving, nil, &TileOpts{
+			Rate:     1000,
+			Progress: func(done, total int) { progress = append(progress, done) },
+		})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		total := len(tiles(60, 40, MaxCoordinates))
+		assert.Len(t, progress, total)
+		assert.Equal(t, total, progress[len(progress)-1])
+		assert.EqualValues(t, total+1, requests)
+
+		assert.Len(t, res.Durations, 60)
+		for i := range sources {
+			assert.Len(t, res.Durations[i], 40)
+			for j := range destinations {
+				assert.Equal(t, math.Abs(float64(i-j)), res.Durations[i][j])
+			}
+			assert.InDelta(t, sources[i].Longitude, res.Sources[i].Location[0], 1e-6)
+		}
+		for j := range destinations {
+			assert.InDelta(t, destinations[j].Longitude, res.Destinations[j].Location[0], 1e-6)
+		}
+	})
+
+	t.Run("Fails after retries", func(t *testing.T) {
+		var requests int32
+		server := matrixServer(t, &requests, failFirst(100, http.StatusTooManyRequests))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+
+		_, err := NewDirectionsMatrix(b).GetDirectionsMatrixTiled(locations(2), locations(2), RoutingDriving, nil, &TileOpts{Rate: 1000, Retries: -1})
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 1, requests)
+	})
+	t.Run("Retries server errors", func(t *testing.T) {
+		var requests int32
+		server := matrixServer(t, &requests, failFirst(1, http.StatusServiceUnavailable))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+
+		_, err := NewDirectionsMatrix(b).GetDirectionsMatrixTiled(locations(2), locations(2), RoutingDriving, nil, &TileOpts{Rate: 1000})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, requests)
+	})
+
+	t.Run("Does not retry invalid requests", func(t *testing.T) {
+		var requests int32
+		server := matrixServer(t, &requests, failFirst(100, http.StatusBadRequest))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+
+		_, err := NewDirectionsMatrix(b).GetDirectionsMatrixTiled(locations(2), locations(2), RoutingDriving, nil, &TileOpts{Rate: 1000})
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 1, requests)
+	})
+
+	t.Run("Returns partial results with errors", func(t *testing.T) {
+		var requests int32
+		// Fail tiles including the first source
+		server := matrixServer(t, &requests, func(n int32, r *http.Request) int {
+			if strings.HasPrefix(path.Base(r.URL.Path), "0.000000,") {
+				return http.StatusBadRequest
+			}
+			return 0
+		})
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+
+		sources, destinations := locations(30), locations(30)
+		res, err := NewDirectionsMatrix(b).GetDirectionsMatrixTiled(sources, destinations, RoutingDriving, nil, &TileOpts{Rate: 1000})
+		tileErr, ok := err.(*TileError)
+		if !assert.True(t, ok) || !assert.NotNil(t, res) || !assert.Len(t, res.Durations, 30) {
+			t.FailNow()
+		}
+
+		// Failed tiles cover the rows of the first source tile
+		srcSize, _ := tileSizes(30, 30, MaxCoordinates)
+		assert.Len(t, tileErr.Failed, len(tiles(30, 30, MaxCoordinates))/3)
+		assert.True(t, tileErr.Contains(0, 29))
+		assert.False(t, tileErr.Contains(29, 0))
+		assert.True(t, IsUnreachable(res.Durations[0][0]))
+		assert.True(t, IsUnreachable(res.Durations[srcSize-1][29]))
+		assert.Equal(t, 29.0, res.Durations[29][0])
+		assert.Equal(t, 0.0, res.Durations[29][29])
+
+		// Partial results can be encoded and analysed
+		data, err := json.Marshal(res)
+		assert.Nil(t, err)
+		decoded := DirectionMatrixResponse{}
+		assert.Nil(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, res.Durations, decoded.Durations)
+
+		assert.Empty(t, res.Durations.Rank(0))
+		assert.Equal(t, []Neighbour{{29, 0}, {28, 1}}, res.Durations.NearestK(29, 2))
+
+		assignments, err := res.Durations.Assign(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, -1, assignments[0])
+		assert.Equal(t, 29, assignments[29])
+
+		clustering, err := res.Durations.KMedoids(2, 0)
+		assert.Nil(t, err)
+		if assert.NotNil(t, clustering) {
+			assert.Len(t, clustering.Clusters, 30)
+			assert.False(t, math.IsNaN(clustering.Cost) || math.IsInf(clustering.Cost, 0))
+		}
+	})
+}
@@ -0,0 +1,106 @@
+/**
+ * go-mapbox Directions Matrix Module Types
+ * Defines directions matrix response types
+ * See https://www.mapbox.com/api-documentation/#matrix-response-format for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directionsmatrix
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Unreachable marks matrix cells with no route, which are null in API responses
+var Unreachable = math.Inf(1)
+
+// IsUnreachable checks whether a matrix value marks a cell with no route
+func IsUnreachable(v float64) bool {
+	return math.IsInf(v, 1)
+}
+
+// Matrix is a matrix of durations (seconds) or distances (meters) indexed by source then destination,
+// with unreachable cells set to Unreachable
+type Matrix [][]float64
+
+// UnmarshalJSON decodes a matrix, converting null cells to Unreachable
+func (m *Matrix) UnmarshalJSON(data []byte) error {
+	raw := [][]*float64{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*m = nil
+		return nil
+	}
+
+	out := make(Matrix, len(raw))
+	for i, row := range raw {
+		out[i] = make([]float64, len(row))
+		for j, v := range row {
+			if v == nil {
+				out[i][j] = Unreachable
+			} else {
+				out[i][j] = *v
+			}
+		}
+	}
+	*m = out
+
+	return nil
+}
+
+// MarshalJSON encodes a matrix, converting Unreachable cells to null
+func (m Matrix) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	raw := make([][]*float64, len(m))
+	for i, row := range m {
+		raw[i] = make([]*float64, len(row))
+		for j := range row {
+			if !IsUnreachable(row[j]) {
+				raw[i][j] = &row[j]
+			}
+		}
+	}
+
+	return json.Marshal(raw)
+}
+
+// DirectionMatrixResponse is the response from GetDirections
+// https://www.mapbox.com/api-documentation/#matrix-response-format
+type DirectionMatrixResponse struct {
+	Code string
+	// Durations and Distances are returned where requested by annotations (durations by default)
+	Durations    Matrix
+	Distances    Matrix
+	Sources      []Waypoint
+	Destinations []Waypoint
+}
+
+// Waypoint is an input point snapped to the road network
+// https://www.mapbox.com/api-documentation/#waypoint-object
+type Waypoint struct {
+	Name     string
+	Location []float64
+	// Distance is the distance in meters the input coordinate was moved when snapped
+	Distance float64
+}
+
+// Codes are direction response Codes
+// https://www.mapbox.com/api-documentation/#matrix-errors
+type Codes string
+
+const (
+	// CodeOK success response
+	CodeOK Codes = "Ok"
+	//CodeProfileNotFound invalid routing profile
+	CodeProfileNotFound Codes = "ProfileNotFound"
+	// CodeInvalidInput invalid input data to the server
+	CodeInvalidInput Codes = "InvalidInput"
+)
@@ -0,0 +1,109 @@
+/**
+ * go-mapbox Directions Matrix Module Type and Option Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package directionsmatrix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestTypes(t *testing.T) {
+
+	t.Run("Decodes null cells as unreachable", func(t *testing.T) {
+		resp := DirectionMatrixResponse{}
+		err := json.Unmarshal([]byte(`{"code":"Ok","durations":[[0,12.5],[null,0]],"distances":[[0,100],[null,0]],`+
+			`"sources":[{"name":"A","location":[1,2],"distance":3.5}]}`), &resp)
+		assert.Nil(t, err)
+
+		assert.Equal(t, Matrix{{0, 12.5}, {Unreachable, 0}}, resp.Durations)
+		assert.Equal(t, 100.0, resp.Distances[0][1])
+		assert.True(t, IsUnreachable(resp.Distances[1][0]))
+		assert.False(t, IsUnreachable(resp.Distances[1][1]))
+		assert.Equal(t, 3.5, resp.Sources[0].Distance)
+	})
+
+	t.Run("Encodes unreachable cells as null", func(t *testing.T) {
+		data, err := json.Marshal(Matrix{{0, Unreachable}})
+		assert.Nil(t, err)
+		assert.Equal(t, `[[0,null]]`, string(data))
+	})
+
+	t.Run("Leaves missing matrices empty", func(t *testing.T) {
+		resp := DirectionMatrixResponse{}
+		err := json.Unmarshal([]byte(`{"code":"Ok","durations":[[0]]}`), &resp)
+		assert.Nil(t, err)
+		assert.Nil(t, resp.Distances)
+	})
+}
+
+func TestRequestOpts(t *testing.T) {
+
+	t.Run("Encodes options", func(t *testing.T) {
+		opts := RequestOpts{FallbackSpeed: 30}
+		opts.SetSources([]int{0, 2})
+		opts.SetAnnotations([]AnnotationType{AnnotationDistance, AnnotationDuration})
+		opts.SetApproaches([]ApproachType{ApproachCurb, "", ApproachUnrestricted})
+		opts.SetDepartAt(time.Date(2019, 5, 2, 15, 0, 0, 0, time.UTC))
+
+		v, err := query.Values(&opts)
+		assert.Nil(t, err)
+		assert.Equal(t, "0;2", v.Get("sources"))
+		assert.Equal(t, "", v.Get("destinations"))
+		assert.Equal(t, "distance,duration", v.Get("annotations"))
+		assert.Equal(t, "curb;;unrestricted", v.Get("approaches"))
+		assert.Equal(t, "30", v.Get("fallback_speed"))
+		assert.Equal(t, "2019-05-02T15:00:00Z", v.Get("depart_at"))
+		assert.Nil(t, opts.Validate(3))
+	})
+
+	t.Run("Validates options", func(t *testing.T) {
+		cases := []RequestOpts{
+			{Sources: []int{3}},
+			{Destinations: []int{-1}},
+			{Annotations: "speed"},
+			{FallbackSpeed: -1},
+			{DepartAt: "tomorrow"},
+			{Approaches: "curb;curb"},
+			{Approaches: "curb;kerb;curb"},
+			{Bearings: "45,90;;400,10"},
+		}
+		for _, c := range cases {
+			assert.NotNil(t, c.Validate(3), "%+v", c)
+		}
+
+		var opts *RequestOpts
+		assert.Nil(t, opts.Validate(3))
+	})
+
+	t.Run("Validates requests before they are issued", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+		d := NewDirectionsMatrix(b)
+
+		locs := make([]base.Location, 11)
+		_, err := d.GetDirectionsMatrix(locs, RoutingDrivingTraffic, nil)
+		assert.NotNil(t, err)
+		_, err = d.GetDirectionsMatrix(locs[:1], RoutingDriving, nil)
+		assert.NotNil(t, err)
+		_, err = d.GetDirectionsMatrix(locs[:2], RoutingDriving, &RequestOpts{Sources: []int{2}})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,308 @@
+/**
+ * go-mapbox Geocoding Module Autocomplete
+ * Provides a type-ahead session that limits the number of forward geocoding requests
+ * See https://www.mapbox.com/api-documentation/#geocoding for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	// DefaultDebounce is the default quiet period after input before a lookup is issued
+	DefaultDebounce = 250 * time.Millisecond
+	// DefaultAutocompleteCacheSize is the default number of queries cached by an autocomplete session
+	DefaultAutocompleteCacheSize = 128
+	// DefaultLimit is the number of results returned by the API when no limit is specified
+	DefaultLimit = 5
+)
+
+// AutocompleteOpts options for an autocomplete session
+type AutocompleteOpts struct {
+	// Debounce is the quiet period after the last input before a lookup is issued
+	Debounce time.Duration
+	// MinLength is the minimum (normalized) query length for which lookups are issued
+	MinLength int
+	// CacheSize is the maximum number of query results retained by the session
+	CacheSize int
+	// Request options for each lookup, Autocomplete is always enabled
+	Request ForwardRequestOpts
+}
+
+// AutocompleteResult is a set of results for an input to an autocomplete session
+type AutocompleteResult struct {
+	// Query is the input the results correspond to
+	Query string
+	// Response is the (possibly locally filtered) forward geocoding response
+	Response *ForwardResponse
+	// Cached indicates the response was answered without an API request
+	Cached bool
+	// Err is any error returned by the lookup
+	Err error
+}
+
+// AutocompleteSession debounces type-ahead input and issues forward geocoding lookups
+// Superseded inputs are dropped before they are issued, and lookups that are already in flight
+// are cancelled when newer input arrives. Inputs that extend a previously
+// answered query are filtered locally where the earlier response was not truncated.
+type AutocompleteSession struct {
+	g    *Geocode
+	opts AutocompleteOpts
+
+	input   chan string
+	done    chan autocompleteLookup
+	results chan AutocompleteResult
+	quit    chan struct{}
+	once    sync.Once
+
+	// Owned by the run loop
+	cache      map[string]*ForwardResponse
+	cacheOrder []string
+	generation uint64
+	cancel     context.CancelFunc
+}
+
+type autocompleteLookup struct {
+	generation uint64
+	query      string
+	key        string
+	resp       *ForwardResponse
+	err        error
+}
+
+// NewAutocompleteSession creates a new autocomplete session, sessions must be closed when no longer required
+func (g *Geocode) NewAutocompleteSession(opts *AutocompleteOpts) *AutocompleteSession {
+	s := &AutocompleteSession{
+		g:       g,
+		input:   make(chan string),
+		done:    make(chan autocompleteLookup),
+		results: make(chan AutocompleteResult, 1),
+		quit:    make(chan struct{}),
+		cache:   make(map[string]*ForwardResponse),
+	}
+
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Debounce == 0 {
+		s.opts.Debounce = DefaultDebounce
+	}
+	if s.opts.CacheSize == 0 {
+		s.opts.CacheSize = DefaultAutocompleteCacheSize
+	}
+	s.opts.Request.Autocomplete = true
+
+	go s.run()
+
+	return s
+}
+
+// Input updates the current query text
+func (s *AutocompleteSession) Input(text string) {
+	select {
+	case s.input <- text:
+	case <-s.quit:
+	}
+}
+
+// Results fetches the result channel for the session
+// Only the latest result is retained if results are not consumed, the channel is closed on Close
+func (s *AutocompleteSession) Results() <-chan AutocompleteResult {
+	return s.results
+}
+
+// Close stops the session
+func (s *AutocompleteSession) Close() {
+	s.once.Do(func() {
+		close(s.quit)
+	})
+}
+
+func (s *AutocompleteSession) run() {
+	defer close(s.results)
+
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	var pending, pendingKey string
+
+	for {
+		select {
+		case text := <-s.input:
+			s.generation++
+			s.cancelLookup()
+			pending, pendingKey = text, normalizeQuery(text)
+
+			if timer != nil {
+				timer.Stop()
+				timerC = nil
+			}
+
+			if len(pendingKey) < s.opts.MinLength || pendingKey == "" {
+				continue
+			}
+
+			// Answer from cache without debouncing where possible
+			if resp, ok := s.lookupCache(pendingKey); ok {
+				s.emit(AutocompleteResult{Query: pending, Response: resp, Cached: true})
+				continue
+			}
+
+			timer = time.NewTimer(s.opts.Debounce)
+			timerC = timer.C
+
+		case <-timerC:
+			timerC = nil
+			ctx, cancel := context.WithCancel(context.Background())
+			s.cancel = cancel
+			go s.lookup(ctx, s.generation, pending, pendingKey)
+
+		case l := <-s.done:
+			if l.err == nil {
+				s.storeCache(l.key, l.resp)
+			}
+			if l.generation == s.generation {
+				s.cancelLookup()
+				s.emit(AutocompleteResult{Query: l.query, Response: l.resp, Err: l.err})
+			}
+
+		case <-s.quit:
+			if timer != nil {
+				timer.Stop()
+			}
+			s.cancelLookup()
+			return
+		}
+	}
+}
+
+// cancelLookup cancels the in-flight lookup, if any
+func (s *AutocompleteSession) cancelLookup() {
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *AutocompleteSession) lookup(ctx context.Context, generation uint64, query, key string) {
+	opts := s.opts.Request
+
+	resp, err := s.g.ForwardContext(ctx, query, &opts)
+
+	select {
+	case s.done <- autocompleteLookup{generation, query, key, resp, err}:
+	case <-s.quit:
+	}
+}
+
+// emit sends a result, replacing any result that has not yet been consumed
+func (s *AutocompleteSession) emit(r AutocompleteResult) {
+	select {
+	case s.results <- r:
+	default:
+		select {
+		case <-s.results:
+		default:
+		}
+		s.results <- r
+	}
+}
+
+// lookupCache finds an exact cached response for a key, or filters the response
+// for the longest cached prefix of the key if that response was not truncated
+func (s *AutocompleteSession) lookupCache(key string) (*ForwardResponse, bool) {
+	if resp, ok := s.cache[key]; ok {
+		return resp, true
+	}
+
+	limit := int(s.opts.Request.Limit)
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	prefix := ""
+	for k := range s.cache {
+		if strings.HasPrefix(key, k) && len(k) > len(prefix) {
+			prefix = k
+		}
+	}
+	if prefix == "" {
+		return nil, false
+	}
+
+	cached := s.cache[prefix]
+	if cached.FeatureCollection == nil || len(cached.Features) >= limit {
+		return nil, false
+	}
+
+	filtered := &ForwardResponse{
+		FeatureCollection: &base.FeatureCollection{
+			Type:        cached.Type,
+			Attribution: cached.Attribution,
+			Features:    make([]base.Feature, 0, len(cached.Features)),
+		},
+		Query: queryTokens(key),
+	}
+	for _, f := range cached.Features {
+		if matchesTokens(f, filtered.Query) {
+			filtered.Features = append(filtered.Features, f)
+		}
+	}
+
+	return filtered, true
+}
+
+func (s *AutocompleteSession) storeCache(key string, resp *ForwardResponse) {
+	if _, ok := s.cache[key]; !ok {
+		s.cacheOrder = append(s.cacheOrder, key)
+	}
+	s.cache[key] = resp
+
+	// Evict oldest entries
+	for len(s.cacheOrder) > s.opts.CacheSize {
+		delete(s.cache, s.cacheOrder[0])
+		s.cacheOrder = s.cacheOrder[1:]
+	}
+}
+
+// normalizeQuery lower-cases a query and collapses whitespace
+func normalizeQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// queryTokens splits a query into alphanumeric tokens
+func queryTokens(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// matchesTokens checks that every query token prefixes a token of the feature place name
+func matchesTokens(f base.Feature, tokens []string) bool {
+	names := queryTokens(f.PlaceName)
+
+	for _, t := range tokens {
+		found := false
+		for _, n := range names {
+			if strings.HasPrefix(n, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+/**
+ * go-mapbox Geocoding Module Autocomplete Tests
+ * See https://www.mapbox.com/api-documentation/#geocoding for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestAutocomplete(t *testing.T) {
+
+	var mu sync.Mutex
+	queries := []string{}
+	started, cancelled := make(chan struct{}), make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".json")
+
+		mu.Lock()
+		queries = append(queries, q)
+		mu.Unlock()
+
+		// Stall slow queries until the client gives up on them
+		if q == "slow" {
+			close(started)
+			<-r.Context().Done()
+			close(cancelled)
+			return
+		}
+
+		resp := base.FeatureCollection{Type: "FeatureCollection"}
+		for _, name := range []string{"London, England, United Kingdom", "Long Beach, California, United States"} {
+			if matchesTokens(base.Feature{PlaceName: name}, queryTokens(q)) {
+				resp.Features = append(resp.Features, base.Feature{PlaceName: name})
+			}
+		}
+		json.NewEncoder(w).Encode(&resp)
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("fake-token")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	b.SetBaseURL(server.URL)
+
+	requests := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, queries...)
+	}
+
+	session := NewGeocode(b).NewAutocompleteSession(&AutocompleteOpts{Debounce: 20 * time.Millisecond, MinLength: 2})
+	defer session.Close()
+
+	t.Run("Debounces input", func(t *testing.T) {
+		session.Input("l")
+		session.Input("lo")
+		session.Input("lon")
+
+		r := <-session.Results()
+		assert.Nil(t, r.Err)
+		assert.EqualValues(t, "lon", r.Query)
+		assert.False(t, r.Cached)
+		assert.Len(t, r.Response.Features, 2)
+
+		assert.EqualValues(t, []string{"lon"}, requests())
+	})
+
+	t.Run("Filters cached prefixes locally", func(t *testing.T) {
+		session.Input("Lond")
+
+		r := <-session.Results()
+		assert.Nil(t, r.Err)
+		assert.True(t, r.Cached)
+		if assert.Len(t, r.Response.Features, 1) {
+			assert.EqualValues(t, "London, England, United Kingdom", r.Response.Features[0].PlaceName)
+		}
+
+		assert.EqualValues(t, []string{"lon"}, requests())
+	})
+
+	t.Run("Reuses exact cached queries", func(t *testing.T) {
+		session.Input("lon ")
+
+		r := <-session.Results()
+		assert.True(t, r.Cached)
+		assert.Len(t, r.Response.Features, 2)
+
+		assert.EqualValues(t, []string{"lon"}, requests())
+	})
+
+	t.Run("Cancels superseded lookups", func(t *testing.T) {
+		session.Input("slow")
+		<-started
+		session.Input("paris")
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("Superseded lookup was not cancelled")
+		}
+
+		r := <-session.Results()
+		assert.Nil(t, r.Err)
+		assert.EqualValues(t, "paris", r.Query)
+		assert.False(t, r.Cached)
+	})
+
+	t.Run("Closes result channel", func(t *testing.T) {
+		session.Close()
+
+		_, ok := <-session.Results()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,190 @@
+/**
+ * go-mapbox Geocoding Module Cache
+ * Provides in-memory and file system caches to avoid repeated geocoding of the same queries
+ * Note that results from the temporary geocoding endpoint must not be stored indefinitely,
+ * see https://www.mapbox.com/tos/ and Geocode.SetCache for details.
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// MemoryCache is a size limited in-memory LRU cache for geocoding results
+type MemoryCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	now func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates a new in-memory cache holding up to size entries
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Save saves a value to the cache, evicting the least recently used entry if the cache is full
+func (mc *MemoryCache) Save(key string, value []byte, ttl time.Duration) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	entry := &memoryCacheEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = mc.now().Add(ttl)
+	}
+
+	if e, ok := mc.entries[key]; ok {
+		e.Value = entry
+		mc.order.MoveToFront(e)
+		return nil
+	}
+
+	mc.entries[key] = mc.order.PushFront(entry)
+
+	for mc.order.Len() > mc.size {
+		e := mc.order.Back()
+		mc.order.Remove(e)
+		delete(mc.entries, e.Value.(*memoryCacheEntry).key)
+	}
+
+	return nil
+}
+
+// Fetch fetches a value from the cache if present and not expired
+func (mc *MemoryCache) Fetch(key string) ([]byte, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	e, ok := mc.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	entry := e.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && mc.now().After(entry.expires) {
+		mc.order.Remove(e)
+		delete(mc.entries, key)
+		return nil, nil
+	}
+
+	mc.order.MoveToFront(e)
+
+	return entry.value, nil
+}
+
+// Len returns the number of entries in the cache
+func (mc *MemoryCache) Len() int {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	return mc.order.Len()
+}
+
+// FileCache is a simple persistent file-based cache for geocoding results
+// Expired entries are removed when fetched, no other mechanisms for removal are implemented
+type FileCache struct {
+	basePath string
+
+	now func() time.Time
+}
+
+type fileCacheEntry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires,omitempty"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// NewFileCache creates a new file cache instance
+func NewFileCache(basePath string) (*FileCache, error) {
+	fc := &FileCache{basePath, time.Now}
+
+	err := os.Mkdir(basePath, 0777)
+	if err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+
+	return fc, nil
+}
+
+func (fc *FileCache) getPath(key string) string {
+	return fmt.Sprintf("%s/%x.json", fc.basePath, sha256.Sum256([]byte(key)))
+}
+
+// Save saves a value to the file cache
+// Values must be valid JSON
+func (fc *FileCache) Save(key string, value []byte, ttl time.Duration) error {
+	entry := fileCacheEntry{Key: key, Value: value}
+	if ttl > 0 {
+		entry.Expires = fc.now().Add(ttl)
+	}
+
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return err
+	}
+
+	// Write via a temporary file so partially written entries are never read
+	path := fc.getPath(key)
+	tmp := fmt.Sprintf("%s.tmp", path)
+
+	if err := ioutil.WriteFile(tmp, data, 0666); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Fetch fetches a value from the file cache if present and not expired
+func (fc *FileCache) Fetch(key string) ([]byte, error) {
+	path := fc.getPath(key)
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := fileCacheEntry{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	// Guard against (unlikely) hash collisions
+	if entry.Key != key {
+		return nil, nil
+	}
+
+	if !entry.Expires.IsZero() && fc.now().After(entry.Expires) {
+		os.Remove(path)
+		return nil, nil
+	}
+
+	return entry.Value, nil
+}
@@ -0,0 +1,131 @@
+/**
+ * go-mapbox Geocoding Module Cache Tests
+ * See https://www.mapbox.com/api-documentation/#geocoding for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestCache(t *testing.T) {
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("Memory cache evicts least recently used entries", func(t *testing.T) {
+		mc := NewMemoryCache(2)
+
+		mc.Save("a", []byte("1"), 0)
+		mc.Save("b", []byte("2"), 0)
+		mc.Fetch("a")
+		mc.Save("c", []byte("3"), 0)
+
+		v, _ := mc.Fetch("a")
+		assert.EqualValues(t, "1", string(v))
+		v, _ = mc.Fetch("b")
+		assert.Nil(t, v)
+		assert.EqualValues(t, 2, mc.Len())
+	})
+
+	t.Run("Memory cache expires entries", func(t *testing.T) {
+		mc := NewMemoryCache(2)
+		mc.now = clock
+
+		mc.Save("a", []byte("1"), time.Hour)
+		mc.Save("b", []byte("2"), 0)
+
+		now = now.Add(2 * time.Hour)
+
+		v, _ := mc.Fetch("a")
+		assert.Nil(t, v)
+		v, _ = mc.Fetch("b")
+		assert.EqualValues(t, "2", string(v))
+	})
+
+	t.Run("File cache persists and expires entries", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "go-mapbox-geocode-cache")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+
+		fc, err := NewFileCache(dir)
+		assert.Nil(t, err)
+		fc.now = clock
+
+		assert.Nil(t, fc.Save("a", []byte(`{"a":1}`), time.Hour))
+		assert.Nil(t, fc.Save("b", []byte(`{"b":2}`), 0))
+
+		// Entries are visible to new instances
+		fc, err = NewFileCache(dir)
+		assert.Nil(t, err)
+		fc.now = clock
+
+		v, err := fc.Fetch("a")
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"a":1}`, string(v))
+
+		now = now.Add(2 * time.Hour)
+
+		v, err = fc.Fetch("a")
+		assert.Nil(t, err)
+		assert.Nil(t, v)
+
+		v, err = fc.Fetch("b")
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"b":2}`, string(v))
+	})
+
+	t.Run("Geocode caches by endpoint", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			json.NewEncoder(w).Encode(&base.FeatureCollection{Type: "FeatureCollection"})
+		}))
+		defer server.Close()
+
+		b, err := base.NewBase("fake-token")
+		assert.Nil(t, err)
+		b.SetBaseURL(server.URL)
+
+		g := NewGeocode(b)
+		g.SetCache(NewMemoryCache(16), 0)
+
+		opts := ForwardRequestOpts{Limit: 1}
+
+		// Temporary results are not cached without a ttl
+		g.Forward("2 Lincoln Memorial Circle NW", &opts)
+		g.Forward("2 lincoln memorial  circle nw", &opts)
+		assert.EqualValues(t, 2, requests)
+
+		// Permanent results are cached and keyed by normalized query
+		g.Forward("2 Lincoln Memorial Circle NW", &opts, true)
+		res, err := g.Forward("2 lincoln memorial  circle nw", &opts, true)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "FeatureCollection", res.Type)
+		assert.EqualValues(t, 3, requests)
+
+		// Options form part of the key
+		g.Forward("2 Lincoln Memorial Circle NW", &ForwardRequestOpts{Limit: 2}, true)
+		assert.EqualValues(t, 4, requests)
+
+		// Temporary results are cached with a ttl
+		g.SetCache(NewMemoryCache(16), time.Hour)
+		g.Reverse(&base.Location{Latitude: 38.889, Longitude: -77.050}, &ReverseRequestOpts{})
+		g.Reverse(&base.Location{Latitude: 38.889, Longitude: -77.050}, &ReverseRequestOpts{})
+		assert.EqualValues(t, 5, requests)
+	})
+}
@@ -10,8 +10,12 @@
 package geocode
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"strings"
+	"log"
+	"net/url"
+	"time"
 
 	"github.com/google/go-querystring/query"
 	"github.com/ryankurte/go-mapbox/lib/base"
@@ -48,23 +52,93 @@ const (
 	POI Type = "poi"
 )
 
+// Cache interface defines an abstract geocoding result cache
+// This can be used to limit the number of API calls required for previously geocoded queries
+type Cache interface {
+	// Save stores a value, a ttl of zero indicates the value does not expire
+	Save(key string, value []byte, ttl time.Duration) error
+	// Fetch fetches a value, returning nil if the key is not found or has expired
+	Fetch(key string) ([]byte, error)
+}
+
 // Geocode api wrapper instance
 type Geocode struct {
-	base *base.Base
+	base     *base.Base
+	cache    Cache
+	cacheTTL time.Duration
 }
 
 // NewGeocode Create a new Geocode API wrapper
 func NewGeocode(base *base.Base) *Geocode {
-	return &Geocode{base}
+	return &Geocode{base, nil, 0}
+}
+
+// SetCache binds a cache into the geocode instance
+// Results from the permanent endpoint are cached indefinitely, while results from the temporary
+// endpoint are only cached for the provided ttl (and not at all if this is zero) in line with
+// the Mapbox terms of service for storing geocoding results
+func (g *Geocode) SetCache(c Cache, ttl time.Duration) {
+	g.cache = c
+	g.cacheTTL = ttl
+}
+
+// cacheFetch attempts to fill inst from the cache, returning true on a cache hit
+func (g *Geocode) cacheFetch(key string, inst interface{}) bool {
+	if g.cache == nil {
+		return false
+	}
+
+	data, err := g.cache.Fetch(key)
+	if err != nil {
+		log.Printf("Cache fetch error (%s)", err)
+		return false
+	}
+	if data == nil {
+		return false
+	}
+
+	if err := json.Unmarshal(data, inst); err != nil {
+		log.Printf("Cache decode error (%s)", err)
+		return false
+	}
+
+	return true
+}
+
+// cacheSave saves inst to the cache where permitted for the endpoint used
+func (g *Geocode) cacheSave(key string, permanent bool, inst interface{}) {
+	if g.cache == nil || (!permanent && g.cacheTTL <= 0) {
+		return
+	}
+
+	ttl := g.cacheTTL
+	if permanent {
+		ttl = 0
+	}
+
+	data, err := json.Marshal(inst)
+	if err != nil {
+		log.Printf("Cache encode error (%s)", err)
+		return
+	}
+
+	if err := g.cache.Save(key, data, ttl); err != nil {
+		log.Printf("Cache save error (%s)", err)
+	}
+}
+
+// cacheKey builds a cache key from the endpoint, normalized query and request options
+func cacheKey(mode, query string, v url.Values) string {
+	return fmt.Sprintf("%s|%s|%s", mode, query, v.Encode())
 }
 
 // ForwardRequestOpts request options fo forward geocoding
 type ForwardRequestOpts struct {
 	Country      string           `url:"country,omitempty"`
-	Proximity    []float64        `url:"proximity,omitempty"`
-	Types        []Type           `url:"types,omitempty"`
+	Proximity    []float64        `url:"proximity,omitempty,comma"`
+	Types        []Type           `url:"types,omitempty,comma"`
 	Autocomplete bool             `url:"autocomplete,omitempty"`
-	BBox         base.BoundingBox `url:"bbox,omitempty"`
+	BBox         base.BoundingBox `url:"bbox,omitempty,comma"`
 	Limit        uint             `url:"limit,omitempty"`
 	FuzzyMatch   bool             `url:"fuzzyMatch,omitempty"`
 	Routing      bool             `url:"routing,omitempty"`
@@ -79,6 +153,11 @@ type ForwardResponse struct {
 // Forward geocode lookup
 // Finds locations from a place name
 func (g *Geocode) Forward(place string, req *ForwardRequestOpts, permanent ...bool) (*ForwardResponse, error) {
+	return g.ForwardContext(context.Background(), place, req, permanent...)
+}
+
+// ForwardContext makes a Forward geocode lookup that is aborted when the provided context is cancelled
+func (g *Geocode) ForwardContext(ctx context.Context, place string, req *ForwardRequestOpts, permanent ...bool) (*ForwardResponse, error) {
 
 	v, err := query.Values(req)
 	if err != nil {
@@ -87,11 +166,21 @@ func (g *Geocode) Forward(place string, req *ForwardRequestOpts, permanent ...bo
 
 	resp := ForwardResponse{}
 
-	queryString := strings.Replace(place, " ", "+", -1)
+	mode := apiMode
 	if len(permanent) > 0 && permanent[0] {
-		err = g.base.Query(apiName, apiVersion, apiModePermanent, fmt.Sprintf("%s.json", queryString), &v, &resp)
-	} else {
-		err = g.base.Query(apiName, apiVersion, apiMode, fmt.Sprintf("%s.json", queryString), &v, &resp)
+		mode = apiModePermanent
+	}
+
+	key := cacheKey(mode, normalizeQuery(place), v)
+	if g.cacheFetch(key, &resp) {
+		return &resp, nil
+	}
+
+	// Escape the place so path characters (eg. "#", "/" or "?") do not truncate the query
+	queryString := url.PathEscape(place)
+	err = g.base.QueryContext(ctx, apiName, apiVersion, mode, fmt.Sprintf("%s.json", queryString), &v, &resp)
+	if err == nil {
+		g.cacheSave(key, mode == apiModePermanent, &resp)
 	}
 
 	return &resp, err
@@ -99,8 +188,8 @@ func (g *Geocode) Forward(place string, req *ForwardRequestOpts, permanent ...bo
 
 // ReverseRequestOpts request options fo reverse geocoding
 type ReverseRequestOpts struct {
-	Types []Type
-	Limit uint
+	Types []Type `url:"types,omitempty,comma"`
+	Limit uint   `url:"limit,omitempty"`
 }
 
 // ReverseResponse is the response to a reverse geocode request
@@ -122,7 +211,15 @@ func (g *Geocode) Reverse(loc *base.Location, req *ReverseRequestOpts) (*Reverse
 
 	queryString := fmt.Sprintf("%f,%f.json", loc.Longitude, loc.Latitude)
 
+	key := cacheKey(apiMode, queryString, v)
+	if g.cacheFetch(key, &resp) {
+		return &resp, nil
+	}
+
 	err = g.base.Query(apiName, apiVersion, apiMode, queryString, &v, &resp)
+	if err == nil {
+		g.cacheSave(key, false, &resp)
+	}
 
 	return &resp, err
 }
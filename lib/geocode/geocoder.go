@@ -0,0 +1,165 @@
+/**
+ * go-mapbox Geocoding Module Geocoder Interface
+ * Defines an abstract geocoder to allow alternative (or fallback) geocoding backends
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Geocoder interface defines an abstract forward and reverse geocoder
+// Implementations map their results onto mapbox style features
+type Geocoder interface {
+	ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error)
+	ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error)
+}
+
+// ForwardGeocode implements Geocoder using the (temporary) mapbox geocoding endpoint
+func (g *Geocode) ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error) {
+	resp, err := g.Forward(place, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.FeatureCollection, nil
+}
+
+// ReverseGeocode implements Geocoder using the mapbox geocoding endpoint
+func (g *Geocode) ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error) {
+	resp, err := g.Reverse(loc, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.FeatureCollection, nil
+}
+
+// FailoverError is returned when all geocoders in a Failover fail
+type FailoverError struct {
+	Errors []error
+}
+
+func (e *FailoverError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("all geocoders failed (%s)", strings.Join(messages, ", "))
+}
+
+// Failover is a composite geocoder that tries each geocoder in order until one succeeds
+type Failover struct {
+	geocoders       []Geocoder
+	fallbackOnEmpty bool
+}
+
+// NewFailover creates a failover geocoder from geocoders in order of preference
+func NewFailover(geocoders ...Geocoder) *Failover {
+	return &Failover{geocoders, false}
+}
+
+// SetFallbackOnEmpty sets whether empty results fall through to the next geocoder
+func (f *Failover) SetFallbackOnEmpty(fallback bool) {
+	f.fallbackOnEmpty = fallback
+}
+
+// ForwardGeocode implements Geocoder
+func (f *Failover) ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error) {
+	return f.try(func(g Geocoder) (*base.FeatureCollection, error) {
+		return g.ForwardGeocode(place, opts)
+	})
+}
+
+// ReverseGeocode implements Geocoder
+func (f *Failover) ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error) {
+	return f.try(func(g Geocoder) (*base.FeatureCollection, error) {
+		return g.ReverseGeocode(loc, opts)
+	})
+}
+
+func (f *Failover) try(lookup func(g Geocoder) (*base.FeatureCollection, error)) (*base.FeatureCollection, error) {
+	errs := []error{}
+	var empty *base.FeatureCollection
+
+	for _, g := range f.geocoders {
+		resp, err := lookup(g)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if f.fallbackOnEmpty && (resp == nil || len(resp.Features) == 0) {
+			if empty == nil {
+				empty = resp
+			}
+			continue
+		}
+		return resp, nil
+	}
+
+	// Empty results take precedence over errors
+	if empty != nil {
+		return empty, nil
+	}
+
+	return nil, &FailoverError{errs}
+}
+
+// getJSON fetches a url and decodes the JSON response into inst
+func getJSON(client *http.Client, url, userAgent string, inst interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if userAgent != "" {
+		request.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("geocoder error: %s (%s)", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, inst)
+}
+
+// filterTypes removes features that do not match any of the requested types
+func filterTypes(fc *base.FeatureCollection, types []Type) {
+	if len(types) == 0 {
+		return
+	}
+
+	filtered := fc.Features[:0]
+	for _, f := range fc.Features {
+		match := false
+		for _, pt := range f.PlaceType {
+			for _, t := range types {
+				if pt == string(t) {
+					match = true
+				}
+			}
+		}
+		if match {
+			filtered = append(filtered, f)
+		}
+	}
+	fc.Features = filtered
+}
@@ -0,0 +1,180 @@
+/**
+ * go-mapbox Geocoding Module Geocoder Tests
+ * Exercises alternative geocoding backends against local stand-in servers
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const nominatimResponse = `{
+	"type": "FeatureCollection",
+	"licence": "Data © OpenStreetMap contributors, ODbL 1.0",
+	"features": [{
+		"type": "Feature",
+		"properties": {
+			"place_id": 1, "osm_type": "way", "osm_id": 123, "place_rank": 30, "category": "place", "type": "house",
+			"importance": 0.6, "name": "", "display_name": "2, Lincoln Memorial Circle NW, Washington, District of Columbia, 20037, United States",
+			"address": {"house_number": "2", "road": "Lincoln Memorial Circle NW", "city": "Washington", "state": "District of Columbia", "postcode": "20037", "country": "United States", "country_code": "us"}
+		},
+		"bbox": [-77.05, 38.88, -77.04, 38.89],
+		"geometry": {"type": "Point", "coordinates": [-77.0502, 38.8893]}
+	}]
+}`
+
+const peliasResponse = `{
+	"geocoding": {"attribution": "https://geocode.earth/guidelines"},
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"geometry": {"type": "Point", "coordinates": [-77.0502, 38.8893]},
+		"properties": {
+			"gid": "openaddresses:address:us/dc:1", "layer": "address", "source": "openaddresses", "name": "2 Lincoln Memorial Circle NW",
+			"label": "2 Lincoln Memorial Circle NW, Washington, DC, USA", "confidence": 0.9, "postalcode": "20037",
+			"country": "United States", "country_gid": "whosonfirst:country:85633793", "country_a": "USA",
+			"region": "District of Columbia", "region_gid": "whosonfirst:region:85688741", "region_a": "DC",
+			"locality": "Washington", "locality_gid": "whosonfirst:locality:85931779"
+		}
+	}]
+}`
+
+type failingGeocoder struct{}
+
+func (f *failingGeocoder) ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (f *failingGeocoder) ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestGeocoders(t *testing.T) {
+
+	var lastQuery map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = r.URL.Query()
+
+		switch r.URL.Path {
+		case "/nominatim/search", "/nominatim/reverse":
+			if r.Header.Get("User-Agent") != "go-mapbox-test" {
+				http.Error(w, "user agent required", http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(nominatimResponse))
+		case "/pelias/v1/search", "/pelias/v1/reverse":
+			w.Write([]byte(peliasResponse))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	nominatim := NewNominatim(server.URL+"/nominatim/", "go-mapbox-test")
+	pelias := NewPelias(server.URL+"/pelias", "")
+
+	t.Run("Nominatim forward geocodes", func(t *testing.T) {
+		res, err := nominatim.ForwardGeocode("2 lincoln memorial circle nw", &ForwardRequestOpts{Limit: 1, Country: "us"})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []string{"1"}, lastQuery["limit"])
+		assert.EqualValues(t, []string{"us"}, lastQuery["countrycodes"])
+
+		if assert.Len(t, res.Features, 1) {
+			f := res.Features[0]
+			assert.EqualValues(t, "address.nominatim:way:123", f.ID)
+			assert.EqualValues(t, "address", contextType(f.ID))
+			assert.EqualValues(t, []string{"address"}, f.PlaceType)
+			assert.EqualValues(t, base.Point{-77.0502, 38.8893}, f.Center)
+			assert.EqualValues(t, "2", f.Text)
+			assert.Contains(t, f.Context, base.Context{ID: "place.nominatim", Text: "Washington"})
+			assert.Contains(t, f.Context, base.Context{ID: "country.nominatim", Text: "United States", ShortCode: "us"})
+		}
+	})
+
+	t.Run("Nominatim filters types", func(t *testing.T) {
+		res, err := nominatim.ReverseGeocode(&base.Location{Latitude: 38.8893, Longitude: -77.0502}, &ReverseRequestOpts{Types: []Type{POI}})
+		assert.Nil(t, err)
+		assert.Len(t, res.Features, 0)
+	})
+
+	t.Run("Pelias forward geocodes", func(t *testing.T) {
+		opts := ForwardRequestOpts{Limit: 2, Proximity: []float64{-77.0, 38.9}, Types: []Type{Address}}
+		res, err := pelias.ForwardGeocode("2 lincoln memorial circle nw", &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []string{"2"}, lastQuery["size"])
+		assert.EqualValues(t, []string{"address,street"}, lastQuery["layers"])
+		assert.EqualValues(t, []string{"38.900000"}, lastQuery["focus.point.lat"])
+
+		if assert.Len(t, res.Features, 1) {
+			f := res.Features[0]
+			assert.EqualValues(t, "address.openaddresses:address:us/dc:1", f.ID)
+			assert.EqualValues(t, "address", contextType(f.ID))
+			assert.EqualValues(t, []string{"address"}, f.PlaceType)
+			assert.EqualValues(t, 0.9, f.Relevance)
+			assert.Contains(t, f.Context, base.Context{ID: "region.whosonfirst:region:85688741", Text: "District of Columbia", ShortCode: "DC"})
+			assert.Contains(t, f.Context, base.Context{ID: "postcode.pelias", Text: "20037"})
+
+			levels := []string{}
+			for _, c := range f.Context {
+				levels = append(levels, contextType(c.ID))
+			}
+			assert.EqualValues(t, []string{"place", "postcode", "region", "country"}, levels)
+		}
+	})
+
+	t.Run("Pelias reverse geocodes", func(t *testing.T) {
+		res, err := pelias.ReverseGeocode(&base.Location{Latitude: 38.8893, Longitude: -77.0502}, nil)
+		assert.Nil(t, err)
+		assert.Len(t, res.Features, 1)
+		assert.EqualValues(t, []string{"38.889300"}, lastQuery["point.lat"])
+	})
+
+	t.Run("Failover tries geocoders in order", func(t *testing.T) {
+		failover := NewFailover(&failingGeocoder{}, pelias, nominatim)
+
+		res, err := failover.ForwardGeocode("2 lincoln memorial circle nw", nil)
+		assert.Nil(t, err)
+		if assert.Len(t, res.Features, 1) {
+			assert.EqualValues(t, "address.openaddresses:address:us/dc:1", res.Features[0].ID)
+		}
+	})
+
+	t.Run("Failover can fall back on empty results", func(t *testing.T) {
+		failover := NewFailover(nominatim, pelias)
+		failover.SetFallbackOnEmpty(true)
+
+		opts := ReverseRequestOpts{Types: []Type{Address}}
+		res, err := failover.ReverseGeocode(&base.Location{Latitude: 38.8893, Longitude: -77.0502}, &opts)
+		assert.Nil(t, err)
+		assert.Len(t, res.Features, 1)
+	})
+
+	t.Run("Failover reports all errors", func(t *testing.T) {
+		failover := NewFailover(&failingGeocoder{}, NewPelias(server.URL+"/missing", ""))
+
+		_, err := failover.ForwardGeocode("2 lincoln memorial circle nw", nil)
+		if assert.IsType(t, &FailoverError{}, err) {
+			assert.Len(t, err.(*FailoverError).Errors, 2)
+		}
+	})
+
+	t.Run("Mapbox implements Geocoder", func(t *testing.T) {
+		var _ Geocoder = &Geocode{}
+	})
+}
@@ -0,0 +1,204 @@
+/**
+ * go-mapbox Geocoding Module Nominatim Backend
+ * Implements the Geocoder interface using a (self-hosted) Nominatim server
+ * See https://nominatim.org/release-docs/latest/api/Overview/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Nominatim geocoder instance
+type Nominatim struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+// NewNominatim creates a new Nominatim geocoder
+// Nominatim usage policies require an identifying user agent
+func NewNominatim(baseURL, userAgent string) *Nominatim {
+	return &Nominatim{strings.TrimSuffix(baseURL, "/"), userAgent, &http.Client{}}
+}
+
+type nominatimFeature struct {
+	Type       string           `json:"type"`
+	BBox       base.BoundingBox `json:"bbox"`
+	Geometry   base.Geometry    `json:"geometry"`
+	Properties struct {
+		PlaceID     int64             `json:"place_id"`
+		OSMType     string            `json:"osm_type"`
+		OSMID       int64             `json:"osm_id"`
+		Name        string            `json:"name"`
+		DisplayName string            `json:"display_name"`
+		PlaceRank   int               `json:"place_rank"`
+		Category    string            `json:"category"`
+		Type        string            `json:"type"`
+		AddressType string            `json:"addresstype"`
+		Importance  float64           `json:"importance"`
+		Address     map[string]string `json:"address"`
+	} `json:"properties"`
+}
+
+type nominatimFeatureCollection struct {
+	Type     string             `json:"type"`
+	Licence  string             `json:"licence"`
+	Features []nominatimFeature `json:"features"`
+}
+
+// nominatimContext maps nominatim address components to mapbox context levels, from most to least specific
+var nominatimContext = []struct {
+	key   string
+	level Type
+}{
+	{"neighbourhood", Neighborhood},
+	{"suburb", Neighborhood},
+	{"village", Locality},
+	{"town", Place},
+	{"city", Place},
+	{"county", District},
+	{"postcode", Postcode},
+	{"state", Region},
+	{"country", Country},
+}
+
+// nominatimType maps a nominatim place rank to a mapbox place type
+// See https://nominatim.org/release-docs/latest/customize/Ranking/
+func nominatimType(rank int, category string) Type {
+	switch {
+	case rank <= 4:
+		return Country
+	case rank <= 9:
+		return Region
+	case rank <= 12:
+		return District
+	case rank <= 16:
+		return Place
+	case rank <= 18:
+		return Locality
+	case rank <= 22:
+		return Neighborhood
+	case category == "place" || category == "building" || category == "highway":
+		return Address
+	default:
+		return POI
+	}
+}
+
+func (n *Nominatim) convert(in *nominatimFeatureCollection) *base.FeatureCollection {
+	fc := &base.FeatureCollection{
+		Type:        "FeatureCollection",
+		Attribution: in.Licence,
+		Features:    make([]base.Feature, len(in.Features)),
+	}
+
+	for i, f := range in.Features {
+		p := f.Properties
+		placeType := nominatimType(p.PlaceRank, p.Category)
+
+		text := p.Name
+		if text == "" {
+			text = strings.Split(p.DisplayName, ",")[0]
+		}
+
+		// IDs are prefixed with the place type as with mapbox results (eg. address.nominatim:way:123)
+		feature := base.Feature{
+			ID:        fmt.Sprintf("%s.nominatim:%s:%d", placeType, p.OSMType, p.OSMID),
+			Type:      "Feature",
+			Text:      text,
+			PlaceName: p.DisplayName,
+			PlaceType: []string{string(placeType)},
+			Relevance: p.Importance,
+			BBox:      f.BBox,
+			Center:    f.Geometry.Coordinates,
+			Geometry:  f.Geometry,
+		}
+		feature.Properties.Category = p.Category
+
+		seen := map[Type]bool{placeType: true}
+		for _, c := range nominatimContext {
+			v, ok := p.Address[c.key]
+			if !ok || seen[c.level] {
+				continue
+			}
+			seen[c.level] = true
+
+			ctx := base.Context{ID: fmt.Sprintf("%s.nominatim", c.level), Text: v}
+			if c.level == Country {
+				ctx.ShortCode = p.Address["country_code"]
+			}
+			feature.Context = append(feature.Context, ctx)
+		}
+
+		fc.Features[i] = feature
+	}
+
+	return fc
+}
+
+// ForwardGeocode implements Geocoder
+// Proximity is not supported by nominatim and is ignored
+func (n *Nominatim) ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error) {
+	v := url.Values{}
+	v.Set("q", place)
+	v.Set("format", "geojson")
+	v.Set("addressdetails", "1")
+
+	if opts == nil {
+		opts = &ForwardRequestOpts{}
+	}
+	if opts.Limit > 0 {
+		v.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	}
+	if opts.Country != "" {
+		v.Set("countrycodes", opts.Country)
+	}
+	if len(opts.BBox) == 4 {
+		v.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f", opts.BBox[0], opts.BBox[1], opts.BBox[2], opts.BBox[3]))
+		v.Set("bounded", "1")
+	}
+
+	resp := nominatimFeatureCollection{}
+	err := getJSON(n.client, fmt.Sprintf("%s/search?%s", n.baseURL, v.Encode()), n.userAgent, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := n.convert(&resp)
+	filterTypes(fc, opts.Types)
+
+	return fc, nil
+}
+
+// ReverseGeocode implements Geocoder
+// Nominatim returns at most one result for reverse lookups
+func (n *Nominatim) ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error) {
+	v := url.Values{}
+	v.Set("lat", fmt.Sprintf("%f", loc.Latitude))
+	v.Set("lon", fmt.Sprintf("%f", loc.Longitude))
+	v.Set("format", "geojson")
+	v.Set("addressdetails", "1")
+
+	resp := nominatimFeatureCollection{}
+	err := getJSON(n.client, fmt.Sprintf("%s/reverse?%s", n.baseURL, v.Encode()), n.userAgent, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := n.convert(&resp)
+	if opts != nil {
+		filterTypes(fc, opts.Types)
+	}
+
+	return fc, nil
+}
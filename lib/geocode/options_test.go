@@ -0,0 +1,73 @@
+/**
+ * go-mapbox Geocoding Module Request Option Tests
+ * See https://www.mapbox.com/api-documentation/#geocoding for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestRequestOpts(t *testing.T) {
+
+	var lastPath string
+	queries := make(chan url.Values, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastPath = r.URL.EscapedPath()
+		queries <- r.URL.Query()
+		w.Write([]byte(`{"type": "FeatureCollection", "features": []}`))
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+	geocode := NewGeocode(b)
+
+	t.Run("Encodes forward list options as comma separated values", func(t *testing.T) {
+		opts := ForwardRequestOpts{
+			Types:     []Type{Address, POI},
+			Proximity: []float64{-77.03, 38.91},
+			BBox:      base.BoundingBox{-77.1, 38.8, -76.9, 39.0},
+		}
+		_, err := geocode.Forward("lincoln memorial", &opts)
+		assert.Nil(t, err)
+
+		q := <-queries
+		assert.Equal(t, []string{"address,poi"}, q["types"])
+		assert.Equal(t, []string{"-77.03,38.91"}, q["proximity"])
+		assert.Equal(t, []string{"-77.1,38.8,-76.9,39"}, q["bbox"])
+	})
+
+	t.Run("Escapes forward queries", func(t *testing.T) {
+		_, err := geocode.Forward("Apt #4, 1/12 Smith St?", nil)
+		assert.Nil(t, err)
+
+		q := <-queries
+		assert.Equal(t, "/geocoding/v5/mapbox.places/Apt%20%234%2C%201%2F12%20Smith%20St%3F.json", lastPath)
+		assert.Equal(t, []string{"test-token"}, q["access_token"])
+	})
+
+	t.Run("Encodes reverse options", func(t *testing.T) {
+		opts := ReverseRequestOpts{Types: []Type{Address, Place}, Limit: 1}
+		_, err := geocode.Reverse(&base.Location{Latitude: 38.91, Longitude: -77.03}, &opts)
+		assert.Nil(t, err)
+
+		q := <-queries
+		assert.Equal(t, []string{"address,place"}, q["types"])
+		assert.Equal(t, []string{"1"}, q["limit"])
+	})
+}
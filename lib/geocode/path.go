@@ -0,0 +1,231 @@
+/**
+ * go-mapbox Geocoding Module Path Helpers
+ * Reverse geocodes a path (such as a route or map matching geometry) into the places it passes through
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	// DefaultSampleSpacing is the default distance between path samples in meters
+	DefaultSampleSpacing = 500.0
+	// DefaultSamplePrecision is the default number of decimal places samples are rounded to for caching
+	DefaultSamplePrecision = 4
+)
+
+// DefaultPathLevels are the place levels reported by default along a path
+var DefaultPathLevels = []Type{Address, Place, Region}
+
+// PathOpts options for reverse geocoding along a path
+type PathOpts struct {
+	// Spacing is the distance between samples in meters
+	Spacing float64
+	// Levels are the place levels to report, street names are reported at the Address level
+	Levels []Type
+	// Precision is the number of decimal places samples are rounded to, samples that round
+	// to the same location are only reverse geocoded once
+	Precision int
+}
+
+// PathSample is a location along a path
+type PathSample struct {
+	Location base.Location
+	// Distance is the distance along the path in meters
+	Distance float64
+}
+
+// PlaceSegment is a contiguous section of a path within a single place
+type PlaceSegment struct {
+	// Level is the place level of the segment
+	Level Type
+	// Name is the place name, or the street name for the Address level
+	Name string
+	// Feature is the feature for the first sample in the segment
+	Feature base.Feature
+	// Start and End are the distances along the path in meters
+	Start, End float64
+	// Samples is the number of samples within the segment
+	Samples int
+}
+
+// Distance is the length of the segment in meters
+func (s *PlaceSegment) Distance() float64 {
+	return s.End - s.Start
+}
+
+// SamplePath samples a path at the provided spacing (in meters), including the start and end points
+func SamplePath(path []base.Location, spacing float64) []PathSample {
+	if len(path) == 0 {
+		return nil
+	}
+
+	samples := []PathSample{{path[0], 0}}
+	travelled, next := 0.0, spacing
+
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		d := base.Distance(a, b)
+
+		for d > 0 && next <= travelled+d {
+			f := (next - travelled) / d
+			samples = append(samples, PathSample{base.Location{
+				Latitude:  a.Latitude + (b.Latitude-a.Latitude)*f,
+				Longitude: a.Longitude + (b.Longitude-a.Longitude)*f,
+			}, next})
+			next += spacing
+		}
+
+		travelled += d
+	}
+
+	// Always include the end of the path
+	if last := samples[len(samples)-1]; travelled-last.Distance > 1e-6 {
+		samples = append(samples, PathSample{path[len(path)-1], travelled})
+	}
+
+	return samples
+}
+
+// ReversePath reverse geocodes a path and collapses consecutive samples in the same place into
+// ordered segments for each requested level. Segment boundaries lie halfway between samples,
+// and sections of the path with no place at a given level are omitted.
+// Paths can be decoded from directions.Route.GetPath or mapmatching.Matchings.GetPath
+func ReversePath(g Geocoder, path []base.Location, opts *PathOpts) (map[Type][]PlaceSegment, error) {
+	o := PathOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Spacing <= 0 {
+		o.Spacing = DefaultSampleSpacing
+	}
+	if len(o.Levels) == 0 {
+		o.Levels = DefaultPathLevels
+	}
+	if o.Precision <= 0 {
+		o.Precision = DefaultSamplePrecision
+	}
+
+	samples := SamplePath(path, o.Spacing)
+	factor := math.Pow10(o.Precision)
+
+	// Reverse geocode each sample, caching by rounded location
+	cache := make(map[string]*base.FeatureCollection)
+	places := make([]map[Type]base.Feature, len(samples))
+
+	for i, s := range samples {
+		loc := base.Location{
+			Latitude:  math.Round(s.Location.Latitude*factor) / factor,
+			Longitude: math.Round(s.Location.Longitude*factor) / factor,
+		}
+		key := fmt.Sprintf("%f,%f", loc.Latitude, loc.Longitude)
+
+		fc, ok := cache[key]
+		if !ok {
+			var err error
+			fc, err = g.ReverseGeocode(&loc, &ReverseRequestOpts{})
+			if err != nil {
+				return nil, err
+			}
+			cache[key] = fc
+		}
+
+		places[i] = placesByLevel(fc)
+	}
+
+	// Collapse samples into segments
+	segments := make(map[Type][]PlaceSegment)
+
+	for _, level := range o.Levels {
+		var current *PlaceSegment
+		currentKey := ""
+
+		for i, s := range samples {
+			f, ok := places[i][level]
+			key := ""
+			if ok {
+				key = placeKey(level, &f)
+			}
+
+			if current != nil && key == currentKey {
+				current.Samples++
+				continue
+			}
+
+			boundary := 0.0
+			if i > 0 {
+				boundary = (samples[i-1].Distance + s.Distance) / 2
+			}
+			if current != nil {
+				current.End = boundary
+				segments[level] = append(segments[level], *current)
+				current = nil
+			}
+
+			currentKey = key
+			if ok {
+				current = &PlaceSegment{Level: level, Name: f.Text, Feature: f, Start: boundary, Samples: 1}
+			}
+		}
+
+		if current != nil {
+			current.End = samples[len(samples)-1].Distance
+			segments[level] = append(segments[level], *current)
+		}
+	}
+
+	return segments, nil
+}
+
+// placesByLevel indexes the features in a reverse geocoding response by place type
+// Levels only available as context of another feature are included with the context ID and text
+func placesByLevel(fc *base.FeatureCollection) map[Type]base.Feature {
+	places := make(map[Type]base.Feature)
+	if fc == nil {
+		return places
+	}
+
+	for _, f := range fc.Features {
+		for _, t := range f.PlaceType {
+			if _, ok := places[Type(t)]; !ok {
+				places[Type(t)] = f
+			}
+		}
+	}
+
+	for _, f := range fc.Features {
+		for _, c := range f.Context {
+			level := Type(contextType(c.ID))
+			if _, ok := places[level]; !ok {
+				places[level] = base.Feature{ID: c.ID, Text: c.Text, PlaceName: c.Text, PlaceType: []string{string(level)}}
+			}
+		}
+	}
+
+	return places
+}
+
+// placeKey identifies a place for collapsing, streets are identified by name as address IDs are unique
+func placeKey(level Type, f *base.Feature) string {
+	if level == Address {
+		return normalizeQuery(f.Text)
+	}
+	return f.ID
+}
+
+// contextType extracts the place type from a context ID (eg. place.123 -> place)
+func contextType(id string) string {
+	if i := strings.Index(id, "."); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
@@ -0,0 +1,89 @@
+/**
+ * go-mapbox Geocoding Module Path Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// pathGeocoder is a stand-in geocoder with streets split at 0.05 degrees longitude
+type pathGeocoder struct {
+	requests int
+}
+
+func (p *pathGeocoder) ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error) {
+	return &base.FeatureCollection{}, nil
+}
+
+func (p *pathGeocoder) ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error) {
+	p.requests++
+
+	street := base.Feature{ID: "address.1", Text: "A Street", PlaceType: []string{"address"}}
+	if loc.Longitude >= 0.05 {
+		street = base.Feature{ID: "address.2", Text: "B Street", PlaceType: []string{"address"}}
+	}
+	street.Context = []base.Context{{ID: "place.1", Text: "Town"}, {ID: "region.1", Text: "Region"}}
+
+	return &base.FeatureCollection{Features: []base.Feature{street}}, nil
+}
+
+func TestReversePath(t *testing.T) {
+
+	path := []base.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.03}, {Latitude: 0, Longitude: 0.1}}
+	length := base.PathLength(path)
+
+	t.Run("Samples paths at the specified spacing", func(t *testing.T) {
+		samples := SamplePath(path, 1000)
+		assert.Len(t, samples, 13)
+		assert.EqualValues(t, 0, samples[0].Distance)
+		assert.InDelta(t, 5000, samples[5].Distance, 1e-6)
+		assert.InDelta(t, 0.0449, samples[5].Location.Longitude, 1e-3)
+		assert.InDelta(t, length, samples[12].Distance, 1e-6)
+	})
+
+	t.Run("Collapses places into segments", func(t *testing.T) {
+		g := &pathGeocoder{}
+
+		segments, err := ReversePath(g, path, &PathOpts{Spacing: 1000})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, 13, g.requests)
+
+		streets := segments[Address]
+		if assert.Len(t, streets, 2) {
+			assert.EqualValues(t, "A Street", streets[0].Name)
+			assert.EqualValues(t, 0, streets[0].Start)
+			assert.EqualValues(t, 5500, streets[0].End)
+			assert.EqualValues(t, 6, streets[0].Samples)
+
+			assert.EqualValues(t, "B Street", streets[1].Name)
+			assert.EqualValues(t, 5500, streets[1].Start)
+			assert.InDelta(t, length, streets[1].End, 1e-6)
+			assert.InDelta(t, length-5500, streets[1].Distance(), 1e-6)
+		}
+
+		if assert.Len(t, segments[Place], 1) {
+			assert.EqualValues(t, "Town", segments[Place][0].Name)
+		}
+		assert.Len(t, segments[Region], 1)
+	})
+
+	t.Run("Caches samples at the same location", func(t *testing.T) {
+		g := &pathGeocoder{}
+
+		stationary := []base.Location{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.00001}}
+		_, err := ReversePath(g, stationary, &PathOpts{Spacing: 0.5})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, g.requests)
+	})
+}
@@ -0,0 +1,223 @@
+/**
+ * go-mapbox Geocoding Module Pelias Backend
+ * Implements the Geocoder interface using a (self-hosted) Pelias server
+ * See https://github.com/pelias/documentation for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Pelias geocoder instance
+type Pelias struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewPelias creates a new Pelias geocoder
+// The API key is optional and only required by hosted instances
+func NewPelias(baseURL, apiKey string) *Pelias {
+	return &Pelias{strings.TrimSuffix(baseURL, "/"), apiKey, &http.Client{}}
+}
+
+type peliasFeature struct {
+	Type       string           `json:"type"`
+	BBox       base.BoundingBox `json:"bbox"`
+	Geometry   base.Geometry    `json:"geometry"`
+	Properties struct {
+		ID              string  `json:"id"`
+		GID             string  `json:"gid"`
+		Layer           string  `json:"layer"`
+		Source          string  `json:"source"`
+		Name            string  `json:"name"`
+		Label           string  `json:"label"`
+		Confidence      float64 `json:"confidence"`
+		PostalCode      string  `json:"postalcode"`
+		Country         string  `json:"country"`
+		CountryGID      string  `json:"country_gid"`
+		CountryA        string  `json:"country_a"`
+		Region          string  `json:"region"`
+		RegionGID       string  `json:"region_gid"`
+		RegionA         string  `json:"region_a"`
+		County          string  `json:"county"`
+		CountyGID       string  `json:"county_gid"`
+		Locality        string  `json:"locality"`
+		LocalityGID     string  `json:"locality_gid"`
+		Neighbourhood   string  `json:"neighbourhood"`
+		NeighbourhoodID string  `json:"neighbourhood_gid"`
+	} `json:"properties"`
+}
+
+type peliasFeatureCollection struct {
+	Type      string          `json:"type"`
+	Features  []peliasFeature `json:"features"`
+	Geocoding struct {
+		Attribution string `json:"attribution"`
+	} `json:"geocoding"`
+}
+
+// peliasLayers maps pelias layers to mapbox place types
+var peliasLayers = map[string]Type{
+	"venue":         POI,
+	"address":       Address,
+	"street":        Address,
+	"neighbourhood": Neighborhood,
+	"borough":       Neighborhood,
+	"localadmin":    Locality,
+	"locality":      Place,
+	"county":        District,
+	"region":        Region,
+	"postalcode":    Postcode,
+	"country":       Country,
+}
+
+// peliasTypeLayers builds the pelias layers argument for a set of mapbox place types
+func peliasTypeLayers(types []Type) string {
+	layers := []string{}
+	for _, t := range types {
+		for l, lt := range peliasLayers {
+			if lt == t {
+				layers = append(layers, l)
+			}
+		}
+	}
+	sort.Strings(layers)
+	return strings.Join(layers, ",")
+}
+
+func (p *Pelias) convert(in *peliasFeatureCollection) *base.FeatureCollection {
+	fc := &base.FeatureCollection{
+		Type:        "FeatureCollection",
+		Attribution: in.Geocoding.Attribution,
+		Features:    make([]base.Feature, len(in.Features)),
+	}
+
+	for i, f := range in.Features {
+		props := f.Properties
+
+		placeType, ok := peliasLayers[props.Layer]
+		if !ok {
+			placeType = POI
+		}
+
+		// IDs are prefixed with the place type as with mapbox results (eg. place.whosonfirst:locality:123)
+		feature := base.Feature{
+			ID:        fmt.Sprintf("%s.%s", placeType, props.GID),
+			Type:      "Feature",
+			Text:      props.Name,
+			PlaceName: props.Label,
+			PlaceType: []string{string(placeType)},
+			Relevance: props.Confidence,
+			BBox:      f.BBox,
+			Center:    f.Geometry.Coordinates,
+			Geometry:  f.Geometry,
+		}
+
+		context := []struct {
+			level     Type
+			id, text  string
+			shortCode string
+		}{
+			{Neighborhood, props.NeighbourhoodID, props.Neighbourhood, ""},
+			{Place, props.LocalityGID, props.Locality, ""},
+			{District, props.CountyGID, props.County, ""},
+			{Postcode, "", props.PostalCode, ""},
+			{Region, props.RegionGID, props.Region, props.RegionA},
+			{Country, props.CountryGID, props.Country, props.CountryA},
+		}
+		for _, c := range context {
+			if c.text == "" || c.level == placeType {
+				continue
+			}
+			id := fmt.Sprintf("%s.%s", c.level, c.id)
+			if c.id == "" {
+				id = fmt.Sprintf("%s.pelias", c.level)
+			}
+			feature.Context = append(feature.Context, base.Context{ID: id, Text: c.text, ShortCode: c.shortCode})
+		}
+
+		fc.Features[i] = feature
+	}
+
+	return fc
+}
+
+func (p *Pelias) query(endpoint string, v url.Values) (*base.FeatureCollection, error) {
+	if p.apiKey != "" {
+		v.Set("api_key", p.apiKey)
+	}
+
+	resp := peliasFeatureCollection{}
+	err := getJSON(p.client, fmt.Sprintf("%s/v1/%s?%s", p.baseURL, endpoint, v.Encode()), "", &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.convert(&resp), nil
+}
+
+// ForwardGeocode implements Geocoder
+func (p *Pelias) ForwardGeocode(place string, opts *ForwardRequestOpts) (*base.FeatureCollection, error) {
+	v := url.Values{}
+	v.Set("text", place)
+
+	if opts == nil {
+		opts = &ForwardRequestOpts{}
+	}
+	if opts.Limit > 0 {
+		v.Set("size", fmt.Sprintf("%d", opts.Limit))
+	}
+	if opts.Country != "" {
+		v.Set("boundary.country", opts.Country)
+	}
+	if len(opts.Proximity) == 2 {
+		v.Set("focus.point.lon", fmt.Sprintf("%f", opts.Proximity[0]))
+		v.Set("focus.point.lat", fmt.Sprintf("%f", opts.Proximity[1]))
+	}
+	if len(opts.BBox) == 4 {
+		v.Set("boundary.rect.min_lon", fmt.Sprintf("%f", opts.BBox[0]))
+		v.Set("boundary.rect.min_lat", fmt.Sprintf("%f", opts.BBox[1]))
+		v.Set("boundary.rect.max_lon", fmt.Sprintf("%f", opts.BBox[2]))
+		v.Set("boundary.rect.max_lat", fmt.Sprintf("%f", opts.BBox[3]))
+	}
+	if layers := peliasTypeLayers(opts.Types); layers != "" {
+		v.Set("layers", layers)
+	}
+
+	endpoint := "search"
+	if opts.Autocomplete {
+		endpoint = "autocomplete"
+	}
+
+	return p.query(endpoint, v)
+}
+
+// ReverseGeocode implements Geocoder
+func (p *Pelias) ReverseGeocode(loc *base.Location, opts *ReverseRequestOpts) (*base.FeatureCollection, error) {
+	v := url.Values{}
+	v.Set("point.lat", fmt.Sprintf("%f", loc.Latitude))
+	v.Set("point.lon", fmt.Sprintf("%f", loc.Longitude))
+
+	if opts != nil {
+		if opts.Limit > 0 {
+			v.Set("size", fmt.Sprintf("%d", opts.Limit))
+		}
+		if layers := peliasTypeLayers(opts.Types); layers != "" {
+			v.Set("layers", layers)
+		}
+	}
+
+	return p.query("reverse", v)
+}
@@ -0,0 +1,306 @@
+/**
+ * go-mapbox Geocoding Module Scoring
+ * Helpers for grading the quality of geocoding matches and disambiguating results
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	// DefaultAcceptThreshold is the default minimum confidence for automatic acceptance
+	DefaultAcceptThreshold = 0.8
+	// DefaultReviewThreshold is the default minimum confidence for manual review
+	DefaultReviewThreshold = 0.5
+	// DefaultMaxDistance is the default distance (in meters) from the proximity hint at which the distance score reaches zero
+	DefaultMaxDistance = 50000.0
+	// DefaultAmbiguityMargin is the default confidence margin within which distinct matches are considered ambiguous
+	DefaultAmbiguityMargin = 0.05
+	// DefaultDuplicateDistance is the default distance (in meters) within which similar features are considered duplicates
+	DefaultDuplicateDistance = 50.0
+)
+
+// Score component weights, components that do not apply are excluded
+const (
+	weightRelevance    = 0.4
+	weightGranularity  = 0.2
+	weightAddressMatch = 0.2
+	weightDistance     = 0.1
+	weightBBox         = 0.1
+)
+
+// Grade is a confidence grade for a geocoding match
+type Grade int
+
+const (
+	// GradeReject indicates the match should not be used
+	GradeReject Grade = iota
+	// GradeReview indicates the match requires manual review
+	GradeReview
+	// GradeAccept indicates the match can be accepted automatically
+	GradeAccept
+)
+
+func (g Grade) String() string {
+	switch g {
+	case GradeAccept:
+		return "accept"
+	case GradeReview:
+		return "review"
+	default:
+		return "reject"
+	}
+}
+
+// granularity ranks place types from least to most specific
+var granularity = map[Type]int{
+	Country:      0,
+	Region:       1,
+	Postcode:     2,
+	District:     3,
+	Place:        4,
+	Locality:     5,
+	Neighborhood: 6,
+	Address:      7,
+	POI:          7,
+}
+
+// ScoreOpts options for scoring geocoding matches
+// Zero values disable the associated score component
+type ScoreOpts struct {
+	// Types are the place types requested
+	Types []Type
+	// AddressNumber is the expected house number
+	AddressNumber string
+	// Proximity is the expected location of the match
+	Proximity *base.Location
+	// MaxDistance is the distance from Proximity (in meters) at which the distance score reaches zero
+	MaxDistance float64
+	// BBox is the expected bounding box of the match
+	BBox base.BoundingBox
+	// AcceptThreshold is the minimum confidence for GradeAccept
+	AcceptThreshold float64
+	// ReviewThreshold is the minimum confidence for GradeReview
+	ReviewThreshold float64
+	// AmbiguityMargin is the margin within which distinct matches are considered ambiguous
+	AmbiguityMargin float64
+	// DuplicateDistance is the distance within which similar features are considered duplicates
+	DuplicateDistance float64
+}
+
+func (o *ScoreOpts) withDefaults() ScoreOpts {
+	opts := ScoreOpts{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.MaxDistance == 0 {
+		opts.MaxDistance = DefaultMaxDistance
+	}
+	if opts.AcceptThreshold == 0 {
+		opts.AcceptThreshold = DefaultAcceptThreshold
+	}
+	if opts.ReviewThreshold == 0 {
+		opts.ReviewThreshold = DefaultReviewThreshold
+	}
+	if opts.AmbiguityMargin == 0 {
+		opts.AmbiguityMargin = DefaultAmbiguityMargin
+	}
+	if opts.DuplicateDistance == 0 {
+		opts.DuplicateDistance = DefaultDuplicateDistance
+	}
+	return opts
+}
+
+// Score is the confidence assessment for a single feature
+// Component scores are in the range 0-1, and are nil where the component does not apply
+type Score struct {
+	Feature *base.Feature
+
+	Relevance    float64
+	Granularity  *float64
+	AddressMatch *float64
+	Distance     *float64
+	BBox         *float64
+
+	// DistanceMeters is the distance from the proximity hint, if provided
+	DistanceMeters float64
+	// Confidence is the weighted combination of component scores
+	Confidence float64
+	// Grade is the confidence grade
+	Grade Grade
+	// Ambiguous indicates another distinct match scored within the ambiguity margin
+	Ambiguous bool
+}
+
+func score(v float64) *float64 {
+	return &v
+}
+
+// ScoreFeature scores a single feature
+func ScoreFeature(f *base.Feature, opts *ScoreOpts) Score {
+	o := opts.withDefaults()
+
+	s := Score{Feature: f, Relevance: f.Relevance}
+	total, weights := weightRelevance*f.Relevance, weightRelevance
+
+	if len(o.Types) > 0 {
+		s.Granularity = score(granularityScore(f, o.Types))
+		total, weights = total+weightGranularity**s.Granularity, weights+weightGranularity
+	}
+
+	if o.AddressNumber != "" {
+		match := 0.0
+		if strings.EqualFold(strings.TrimSpace(f.Address), strings.TrimSpace(o.AddressNumber)) {
+			match = 1.0
+		}
+		s.AddressMatch = score(match)
+		total, weights = total+weightAddressMatch*match, weights+weightAddressMatch
+	}
+
+	if o.Proximity != nil && len(f.Center) == 2 {
+		s.DistanceMeters = base.Distance(*o.Proximity, base.PointToLocation(f.Center))
+		d := 1 - s.DistanceMeters/o.MaxDistance
+		if d < 0 {
+			d = 0
+		}
+		s.Distance = score(d)
+		total, weights = total+weightDistance*d, weights+weightDistance
+	}
+
+	if len(o.BBox) == 4 && len(f.Center) == 2 {
+		contained := 0.0
+		if o.BBox.Contains(base.PointToLocation(f.Center)) {
+			contained = 1.0
+		}
+		s.BBox = score(contained)
+		total, weights = total+weightBBox*contained, weights+weightBBox
+	}
+
+	s.Confidence = total / weights
+	s.Grade = o.grade(s.Confidence)
+
+	return s
+}
+
+func (o *ScoreOpts) grade(confidence float64) Grade {
+	switch {
+	case confidence >= o.AcceptThreshold:
+		return GradeAccept
+	case confidence >= o.ReviewThreshold:
+		return GradeReview
+	default:
+		return GradeReject
+	}
+}
+
+// granularityScore compares the most specific feature place type with the most specific requested type
+// Features as or more specific than requested score 1, coarser features are penalised by the difference
+func granularityScore(f *base.Feature, types []Type) float64 {
+	want := 0
+	for _, t := range types {
+		if g := granularity[t]; g > want {
+			want = g
+		}
+	}
+
+	have := -1
+	for _, t := range f.PlaceType {
+		if g, ok := granularity[Type(t)]; ok && g > have {
+			have = g
+		}
+	}
+	if have < 0 {
+		return 0
+	}
+	if have >= want {
+		return 1
+	}
+
+	return 1 - float64(want-have)/float64(granularity[Address])
+}
+
+// ScoreFeatures scores and deduplicates a feature collection, returning scores in order of decreasing confidence
+func ScoreFeatures(fc *base.FeatureCollection, opts *ScoreOpts) []Score {
+	if fc == nil {
+		return nil
+	}
+
+	o := opts.withDefaults()
+	features := Deduplicate(fc.Features, o.DuplicateDistance)
+
+	scores := make([]Score, len(features))
+	for i := range features {
+		scores[i] = ScoreFeature(&features[i], &o)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Confidence > scores[j].Confidence
+	})
+
+	return scores
+}
+
+// Disambiguate scores a feature collection and returns the best match, or nil if there are no features
+// Where another distinct match scores within the ambiguity margin the best match is marked ambiguous
+// and its grade is limited to GradeReview
+func Disambiguate(fc *base.FeatureCollection, opts *ScoreOpts) *Score {
+	scores := ScoreFeatures(fc, opts)
+	if len(scores) == 0 {
+		return nil
+	}
+
+	o := opts.withDefaults()
+	best := scores[0]
+
+	if len(scores) > 1 && best.Confidence-scores[1].Confidence < o.AmbiguityMargin {
+		best.Ambiguous = true
+		if best.Grade == GradeAccept {
+			best.Grade = GradeReview
+		}
+	}
+
+	return &best
+}
+
+// Deduplicate removes near-identical features, retaining the first of each set of duplicates
+// Features are duplicates where they have the same primary place type and name and are within
+// tolerance meters of each other
+func Deduplicate(features []base.Feature, tolerance float64) []base.Feature {
+	out := make([]base.Feature, 0, len(features))
+
+	for _, f := range features {
+		duplicate := false
+		for _, o := range out {
+			if isDuplicate(&f, &o, tolerance) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			out = append(out, f)
+		}
+	}
+
+	return out
+}
+
+func isDuplicate(a, b *base.Feature, tolerance float64) bool {
+	if len(a.PlaceType) == 0 || len(b.PlaceType) == 0 || a.PlaceType[0] != b.PlaceType[0] {
+		return false
+	}
+	if normalizeQuery(a.Text) != normalizeQuery(b.Text) || !strings.EqualFold(a.Address, b.Address) {
+		return false
+	}
+	if len(a.Center) != 2 || len(b.Center) != 2 {
+		return false
+	}
+	return base.Distance(base.PointToLocation(a.Center), base.PointToLocation(b.Center)) <= tolerance
+}
@@ -0,0 +1,110 @@
+/**
+ * go-mapbox Geocoding Module Scoring Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestScoring(t *testing.T) {
+
+	address := base.Feature{
+		ID:        "address.1",
+		Text:      "Lincoln Memorial Circle NW",
+		Address:   "2",
+		PlaceType: []string{"address"},
+		Relevance: 1,
+		Center:    base.Point{-77.0502, 38.8893},
+	}
+	place := base.Feature{
+		ID:        "place.1",
+		Text:      "Washington",
+		PlaceType: []string{"place"},
+		Relevance: 1,
+		Center:    base.Point{-77.0366, 38.8951},
+	}
+	faraway := base.Feature{
+		ID:        "address.2",
+		Text:      "Lincoln Memorial Circle NW",
+		Address:   "2",
+		PlaceType: []string{"address"},
+		Relevance: 1,
+		Center:    base.Point{-122.42, 37.78},
+	}
+
+	proximity := &base.Location{Latitude: 38.89, Longitude: -77.04}
+
+	t.Run("Scores relevance alone without hints", func(t *testing.T) {
+		s := ScoreFeature(&place, nil)
+		assert.EqualValues(t, 1, s.Confidence)
+		assert.EqualValues(t, GradeAccept, s.Grade)
+		assert.Nil(t, s.Granularity)
+	})
+
+	t.Run("Penalises coarse matches", func(t *testing.T) {
+		opts := ScoreOpts{Types: []Type{Address}, AddressNumber: "2"}
+
+		s := ScoreFeature(&address, &opts)
+		assert.EqualValues(t, 1, s.Confidence)
+		assert.EqualValues(t, GradeAccept, s.Grade)
+
+		s = ScoreFeature(&place, &opts)
+		assert.EqualValues(t, 0, *s.AddressMatch)
+		assert.InDelta(t, 1-3.0/7, *s.Granularity, 1e-9)
+		assert.EqualValues(t, GradeReview, s.Grade)
+	})
+
+	t.Run("Penalises distant and out of bounds matches", func(t *testing.T) {
+		opts := ScoreOpts{Proximity: proximity, BBox: base.BoundingBox{-78, 38, -76, 39}, ReviewThreshold: 0.9}
+
+		s := ScoreFeature(&address, &opts)
+		assert.InDelta(t, 1000, s.DistanceMeters, 500)
+		assert.EqualValues(t, 1, *s.BBox)
+		assert.EqualValues(t, GradeAccept, s.Grade)
+
+		s = ScoreFeature(&faraway, &opts)
+		assert.EqualValues(t, 0, *s.Distance)
+		assert.EqualValues(t, 0, *s.BBox)
+		assert.EqualValues(t, GradeReject, s.Grade)
+	})
+
+	t.Run("Deduplicates near-identical features", func(t *testing.T) {
+		duplicate := address
+		duplicate.ID = "address.3"
+		duplicate.Text = "lincoln memorial circle  nw"
+		duplicate.Center = base.Point{-77.0503, 38.8893}
+
+		out := Deduplicate([]base.Feature{address, duplicate, faraway, place}, DefaultDuplicateDistance)
+		assert.Len(t, out, 3)
+		assert.EqualValues(t, "address.1", out[0].ID)
+	})
+
+	t.Run("Disambiguates matches", func(t *testing.T) {
+		opts := ScoreOpts{Types: []Type{Address}, Proximity: proximity}
+
+		best := Disambiguate(&base.FeatureCollection{Features: []base.Feature{faraway, place, address}}, &opts)
+		if assert.NotNil(t, best) {
+			assert.EqualValues(t, "address.1", best.Feature.ID)
+			assert.False(t, best.Ambiguous)
+			assert.EqualValues(t, GradeAccept, best.Grade)
+		}
+
+		// Equally good distinct matches are ambiguous
+		best = Disambiguate(&base.FeatureCollection{Features: []base.Feature{faraway, address}}, &ScoreOpts{Types: []Type{Address}})
+		if assert.NotNil(t, best) {
+			assert.True(t, best.Ambiguous)
+			assert.EqualValues(t, GradeReview, best.Grade)
+		}
+
+		assert.Nil(t, Disambiguate(&base.FeatureCollection{}, nil))
+	})
+}
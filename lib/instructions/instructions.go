@@ -0,0 +1,225 @@
+/**
+ * go-mapbox Instructions Module
+ * Generates turn-by-turn instruction text from route steps using locale templates,
+ * for languages Mapbox does not provide or routes from other engines
+ * Based on the approach of https://github.com/Project-OSRM/osrm-text-instructions
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package instructions
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+// ExitModifier is the modifier key used for roundabout and rotary templates when an exit number is known
+const ExitModifier = "exit"
+
+// Templates are the instruction templates for a maneuver type and modifier
+// Templates may include the placeholders {modifier}, {direction}, {way_name}, {destination} and {nth},
+// and are written as they would appear mid-sentence (eg. "turn {modifier}") as they are capitalized when used
+type Templates struct {
+	// Default is used when the step has no name or destination
+	Default string
+	// Name is used when the step has a name or ref
+	Name string
+	// Destination is used when the step has destinations
+	Destination string
+}
+
+// Unit is the singular and plural phrasing of a distance unit, with a {value} placeholder
+type Unit struct {
+	One, Other string
+}
+
+// Locale contains the templates and phrases for a language
+type Locale struct {
+	// Language is the language code (eg. en)
+	Language string
+	// Instructions maps maneuver types to modifiers to templates, the empty modifier is the default
+	// for the type, and unknown maneuver types use the turn templates
+	Instructions map[string]map[directions.StepModifier]Templates
+	// Modifiers are the localized maneuver modifiers
+	Modifiers map[directions.StepModifier]string
+	// Directions are the localized compass directions from north, clockwise in 45 degree steps
+	Directions [8]string
+	// Ordinals are the localized ordinals from first, used for roundabout exits
+	Ordinals []string
+	// In phrases an instruction at a distance, with {distance} and {instruction} placeholders
+	In string
+	// Units are the distance units, phrased for use in the In template
+	Meters, Kilometers, Feet, Miles Unit
+	// DecimalSeparator is used when formatting fractional distances
+	DecimalSeparator string
+}
+
+// Locales are the available locales by language code
+// Additional locales can be added by callers before use
+var Locales = map[string]*Locale{
+	"en": English,
+	"de": German,
+}
+
+// Units selects distance units
+type Units string
+
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+)
+
+// Generator generates instructions for a locale and units
+type Generator struct {
+	locale *Locale
+	units  Units
+}
+
+// NewGenerator creates an instruction generator for the provided language and units
+func NewGenerator(language string, units Units) (*Generator, error) {
+	locale, ok := Locales[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported instruction language '%s'", language)
+	}
+	if units != UnitsMetric && units != UnitsImperial {
+		return nil, fmt.Errorf("Unsupported instruction units '%s'", units)
+	}
+	return &Generator{locale, units}, nil
+}
+
+// Instruction generates the instruction text for a route step
+func (g *Generator) Instruction(step *directions.RouteStep) string {
+	return capitalize(g.instruction(step))
+}
+
+// InstructionIn generates the instruction text for a route step at a distance (in meters),
+// for example "In 200 meters, turn left onto Main Street"
+func (g *Generator) InstructionIn(step *directions.RouteStep, distance float64) string {
+	return capitalize(replace(g.locale.In, map[string]string{
+		"distance":    g.Distance(distance),
+		"instruction": g.instruction(step),
+	}))
+}
+
+// Distance formats a distance in meters using the generator units, rounding to a precision
+// appropriate for spoken or displayed instructions
+func (g *Generator) Distance(meters float64) string {
+	l := g.locale
+
+	if g.units == UnitsImperial {
+		feet := meters / 0.3048
+		if feet < 528 {
+			return g.unit(l.Feet, math.Max(roundTo(feet, 50), 50), 0)
+		}
+		return g.unit(l.Miles, roundTo(feet/5280, 0.1), 1)
+	}
+
+	if meters < 1000 {
+		step := 10.0
+		if meters < 100 {
+			step = 5
+		}
+		return g.unit(l.Meters, math.Max(roundTo(meters, step), step), 0)
+	}
+	return g.unit(l.Kilometers, roundTo(meters/1000, 0.1), 1)
+}
+
+func (g *Generator) unit(u Unit, value float64, decimals int) string {
+	s := fmt.Sprintf("%.*f", decimals, value)
+	s = strings.TrimSuffix(s, ".0")
+	s = strings.Replace(s, ".", g.locale.DecimalSeparator, 1)
+
+	phrase := u.Other
+	if value == 1 {
+		phrase = u.One
+	}
+	return replace(phrase, map[string]string{"value": s})
+}
+
+func (g *Generator) instruction(step *directions.RouteStep) string {
+	l := g.locale
+	m := step.Maneuver
+
+	templates := g.templates(m)
+
+	wayName := step.Name
+	if step.Ref != "" {
+		if wayName == "" {
+			wayName = step.Ref
+		} else if !strings.Contains(wayName, step.Ref) {
+			wayName = fmt.Sprintf("%s (%s)", wayName, step.Ref)
+		}
+	}
+
+	template := templates.Default
+	switch {
+	case step.Destinations != "" && templates.Destination != "":
+		template = templates.Destination
+	case wayName != "" && templates.Name != "":
+		template = templates.Name
+	}
+
+	nth := ""
+	if m.Exit > 0 && m.Exit <= len(l.Ordinals) {
+		nth = l.Ordinals[m.Exit-1]
+	}
+
+	return replace(template, map[string]string{
+		"modifier":    l.Modifiers[m.Modifier],
+		"direction":   l.Directions[compassIndex(m.BearingAfter)],
+		"way_name":    wayName,
+		"destination": step.Destinations,
+		"nth":         nth,
+	})
+}
+
+// templates finds the templates for a maneuver, falling back to the default for the type
+// and then to turn templates for unknown types
+func (g *Generator) templates(m directions.StepManeuver) Templates {
+	byModifier, ok := g.locale.Instructions[m.Type]
+	if !ok {
+		byModifier = g.locale.Instructions[directions.ManeuverTurn]
+	}
+
+	if m.Exit > 0 && m.Exit <= len(g.locale.Ordinals) {
+		if t, ok := byModifier[ExitModifier]; ok {
+			return t
+		}
+	}
+	if t, ok := byModifier[m.Modifier]; ok {
+		return t
+	}
+	return byModifier[""]
+}
+
+// compassIndex converts a bearing to an index into the 8 compass directions
+func compassIndex(bearing float64) int {
+	return int(math.Floor(math.Mod(bearing+22.5, 360)/45+8)) % 8
+}
+
+func roundTo(value, step float64) float64 {
+	return math.Round(value/step) * step
+}
+
+func replace(template string, values map[string]string) string {
+	for k, v := range values {
+		template = strings.Replace(template, "{"+k+"}", v, -1)
+	}
+	// Collapse whitespace left by empty placeholders
+	return strings.Join(strings.Fields(template), " ")
+}
+
+func capitalize(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[n:]
+}
@@ -0,0 +1,89 @@
+/**
+ * go-mapbox Instructions Module Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package instructions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+func step(maneuver, modifier, name, ref, destinations string, exit int, bearing float64) *directions.RouteStep {
+	return &directions.RouteStep{
+		Name:         name,
+		Ref:          ref,
+		Destinations: destinations,
+		Maneuver: directions.StepManeuver{
+			Type:         maneuver,
+			Modifier:     directions.StepModifier(modifier),
+			Exit:         exit,
+			BearingAfter: bearing,
+		},
+	}
+}
+
+func TestInstructions(t *testing.T) {
+
+	en, err := NewGenerator("en", UnitsMetric)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	de, err := NewGenerator("de", UnitsMetric)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	imperial, err := NewGenerator("en", UnitsImperial)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Rejects unknown locales and units", func(t *testing.T) {
+		_, err := NewGenerator("xx", UnitsMetric)
+		assert.NotNil(t, err)
+		_, err = NewGenerator("en", Units("parsecs"))
+		assert.NotNil(t, err)
+	})
+
+	tests := []struct {
+		name   string
+		step   *directions.RouteStep
+		en, de string
+	}{
+		{"Depart", step("depart", "", "Main Street", "", "", 0, 92), "Head east on Main Street", "In Richtung Osten auf Main Street fahren"},
+		{"Turn", step("turn", "left", "", "", "", 0, 0), "Turn left", "Links abbiegen"},
+		{"Turn with ref", step("turn", "sharp right", "Pacific Highway", "A1", "", 0, 0), "Turn sharp right onto Pacific Highway (A1)", "Scharf rechts abbiegen auf Pacific Highway (A1)"},
+		{"Straight", step("new name", "straight", "Oak Avenue", "", "", 0, 0), "Continue onto Oak Avenue", "Weiterfahren auf Oak Avenue"},
+		{"Destination", step("off ramp", "slight right", "", "", "Baltimore", 0, 0), "Take the exit on the slight right towards Baltimore", "Die Ausfahrt leicht rechts Richtung Baltimore nehmen"},
+		{"Roundabout exit", step("roundabout", "right", "High Street", "", "", 2, 0), "Enter the roundabout and take the 2nd exit onto High Street", "Im Kreisverkehr die zweite Ausfahrt nehmen auf High Street"},
+		{"Roundabout without exit", step("roundabout", "right", "", "", "", 0, 0), "Enter the roundabout", "In den Kreisverkehr fahren"},
+		{"Arrive", step("arrive", "right", "", "", "", 0, 0), "You have arrived at your destination, on the right", "Sie haben Ihr Ziel erreicht, es befindet sich rechts"},
+		{"Unknown types", step("hovercraft", "left", "", "", "", 0, 0), "Turn left", "Links abbiegen"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualValues(t, tt.en, en.Instruction(tt.step))
+			assert.EqualValues(t, tt.de, de.Instruction(tt.step))
+		})
+	}
+
+	t.Run("Phrases distances", func(t *testing.T) {
+		s := step("turn", "left", "Main Street", "", "", 0, 0)
+		assert.EqualValues(t, "In 200 meters, turn left onto Main Street", en.InstructionIn(s, 203))
+		assert.EqualValues(t, "In 200 Metern links abbiegen auf Main Street", de.InstructionIn(s, 203))
+		assert.EqualValues(t, "In 1.5 kilometers, turn left onto Main Street", en.InstructionIn(s, 1520))
+		assert.EqualValues(t, "In 1,5 Kilometern links abbiegen auf Main Street", de.InstructionIn(s, 1520))
+
+		assert.EqualValues(t, "500 feet", imperial.Distance(152))
+		assert.EqualValues(t, "1 mile", imperial.Distance(1609))
+		assert.EqualValues(t, "2.5 miles", imperial.Distance(4000))
+		assert.EqualValues(t, "5 meters", en.Distance(1))
+	})
+}
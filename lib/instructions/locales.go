@@ -0,0 +1,189 @@
+/**
+ * go-mapbox Instructions Module Locales
+ * Built in English and German instruction templates
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package instructions
+
+import (
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+// English instruction locale
+var English = &Locale{
+	Language: "en",
+	Instructions: map[string]map[directions.StepModifier]Templates{
+		directions.ManeuverDepart: {
+			"": {"head {direction}", "head {direction} on {way_name}", "head {direction} towards {destination}"},
+		},
+		directions.ManeuverArrive: {
+			"":                              {"you have arrived at your destination", "", ""},
+			directions.StepModifierLeft:     {"you have arrived at your destination, on the left", "", ""},
+			directions.StepModifierRight:    {"you have arrived at your destination, on the right", "", ""},
+			directions.StepModifierStraight: {"you have arrived at your destination, straight ahead", "", ""},
+		},
+		directions.ManeuverTurn: {
+			"":                              {"turn {modifier}", "turn {modifier} onto {way_name}", "turn {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"go straight", "go straight onto {way_name}", "go straight towards {destination}"},
+			directions.StepModifierUTurn:    {"make a U-turn", "make a U-turn onto {way_name}", "make a U-turn towards {destination}"},
+		},
+		directions.ManeuverContinue: {
+			"":                              {"continue {modifier}", "continue {modifier} onto {way_name}", "continue {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"continue straight", "continue onto {way_name}", "continue towards {destination}"},
+			directions.StepModifierUTurn:    {"make a U-turn", "make a U-turn onto {way_name}", "make a U-turn towards {destination}"},
+		},
+		directions.ManeuverNewName: {
+			"":                              {"continue {modifier}", "continue {modifier} onto {way_name}", "continue {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"continue straight", "continue onto {way_name}", "continue towards {destination}"},
+		},
+		directions.ManeuverNotification: {
+			"":                              {"continue {modifier}", "continue {modifier} onto {way_name}", "continue {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"continue straight", "continue onto {way_name}", "continue towards {destination}"},
+		},
+		directions.ManeuverMerge: {
+			"":                              {"merge {modifier}", "merge {modifier} onto {way_name}", "merge {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"merge", "merge onto {way_name}", "merge towards {destination}"},
+		},
+		directions.ManeuverOnRamp: {
+			"":                              {"take the ramp on the {modifier}", "take the ramp on the {modifier} onto {way_name}", "take the ramp on the {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"take the ramp", "take the ramp onto {way_name}", "take the ramp towards {destination}"},
+		},
+		directions.ManeuverOffRamp: {
+			"":                              {"take the exit on the {modifier}", "take the exit on the {modifier} onto {way_name}", "take the exit on the {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"take the exit", "take the exit onto {way_name}", "take the exit towards {destination}"},
+		},
+		directions.ManeuverFork: {
+			"":                              {"keep {modifier} at the fork", "keep {modifier} onto {way_name}", "keep {modifier} towards {destination}"},
+			directions.StepModifierStraight: {"keep straight at the fork", "keep straight onto {way_name}", "keep straight towards {destination}"},
+		},
+		directions.ManeuverEndOfRoad: {
+			"": {"turn {modifier} at the end of the road", "turn {modifier} onto {way_name} at the end of the road", "turn {modifier} at the end of the road towards {destination}"},
+		},
+		directions.ManeuverRoundabout: {
+			"":           {"enter the roundabout", "enter the roundabout and exit onto {way_name}", "enter the roundabout and exit towards {destination}"},
+			ExitModifier: {"enter the roundabout and take the {nth} exit", "enter the roundabout and take the {nth} exit onto {way_name}", "enter the roundabout and take the {nth} exit towards {destination}"},
+		},
+		directions.ManeuverRotary: {
+			"":           {"enter the traffic circle", "enter the traffic circle and exit onto {way_name}", "enter the traffic circle and exit towards {destination}"},
+			ExitModifier: {"enter the traffic circle and take the {nth} exit", "enter the traffic circle and take the {nth} exit onto {way_name}", "enter the traffic circle and take the {nth} exit towards {destination}"},
+		},
+		directions.ManeuverRoundaboutTurn: {
+			"": {"at the roundabout, turn {modifier}", "at the roundabout, turn {modifier} onto {way_name}", "at the roundabout, turn {modifier} towards {destination}"},
+		},
+		directions.ManeuverExitRoundabout: {
+			"": {"exit the roundabout", "exit the roundabout onto {way_name}", "exit the roundabout towards {destination}"},
+		},
+		directions.ManeuverExitRotary: {
+			"": {"exit the traffic circle", "exit the traffic circle onto {way_name}", "exit the traffic circle towards {destination}"},
+		},
+	},
+	Modifiers: map[directions.StepModifier]string{
+		directions.StepModifierUTurn:       "U-turn",
+		directions.StepModifierSharpRight:  "sharp right",
+		directions.StepModifierRight:       "right",
+		directions.StepModifierSlightRight: "slight right",
+		directions.StepModifierStraight:    "straight",
+		directions.StepModifierSharpLeft:   "sharp left",
+		directions.StepModifierLeft:        "left",
+		directions.StepModifierSlightLeft:  "slight left",
+	},
+	Directions:       [8]string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"},
+	Ordinals:         []string{"1st", "2nd", "3rd", "4th", "5th", "6th", "7th", "8th", "9th", "10th"},
+	In:               "in {distance}, {instruction}",
+	Meters:           Unit{"{value} meter", "{value} meters"},
+	Kilometers:       Unit{"{value} kilometer", "{value} kilometers"},
+	Feet:             Unit{"{value} foot", "{value} feet"},
+	Miles:            Unit{"{value} mile", "{value} miles"},
+	DecimalSeparator: ".",
+}
+
+// German instruction locale
+var German = &Locale{
+	Language: "de",
+	Instructions: map[string]map[directions.StepModifier]Templates{
+		directions.ManeuverDepart: {
+			"": {"in Richtung {direction} fahren", "in Richtung {direction} auf {way_name} fahren", "in Richtung {direction} fahren, Richtung {destination}"},
+		},
+		directions.ManeuverArrive: {
+			"":                              {"Sie haben Ihr Ziel erreicht", "", ""},
+			directions.StepModifierLeft:     {"Sie haben Ihr Ziel erreicht, es befindet sich links", "", ""},
+			directions.StepModifierRight:    {"Sie haben Ihr Ziel erreicht, es befindet sich rechts", "", ""},
+			directions.StepModifierStraight: {"Sie haben Ihr Ziel erreicht, es befindet sich geradeaus", "", ""},
+		},
+		directions.ManeuverTurn: {
+			"":                              {"{modifier} abbiegen", "{modifier} abbiegen auf {way_name}", "{modifier} abbiegen Richtung {destination}"},
+			directions.StepModifierStraight: {"geradeaus weiterfahren", "geradeaus weiterfahren auf {way_name}", "geradeaus weiterfahren Richtung {destination}"},
+			directions.StepModifierUTurn:    {"wenden", "wenden auf {way_name}", "wenden Richtung {destination}"},
+		},
+		directions.ManeuverContinue: {
+			"":                              {"{modifier} weiterfahren", "{modifier} weiterfahren auf {way_name}", "{modifier} weiterfahren Richtung {destination}"},
+			directions.StepModifierStraight: {"geradeaus weiterfahren", "weiterfahren auf {way_name}", "weiterfahren Richtung {destination}"},
+			directions.StepModifierUTurn:    {"wenden", "wenden auf {way_name}", "wenden Richtung {destination}"},
+		},
+		directions.ManeuverNewName: {
+			"":                              {"{modifier} weiterfahren", "{modifier} weiterfahren auf {way_name}", "{modifier} weiterfahren Richtung {destination}"},
+			directions.StepModifierStraight: {"geradeaus weiterfahren", "weiterfahren auf {way_name}", "weiterfahren Richtung {destination}"},
+		},
+		directions.ManeuverNotification: {
+			"":                              {"{modifier} weiterfahren", "{modifier} weiterfahren auf {way_name}", "{modifier} weiterfahren Richtung {destination}"},
+			directions.StepModifierStraight: {"geradeaus weiterfahren", "weiterfahren auf {way_name}", "weiterfahren Richtung {destination}"},
+		},
+		directions.ManeuverMerge: {
+			"":                              {"{modifier} einfädeln", "{modifier} einfädeln auf {way_name}", "{modifier} einfädeln Richtung {destination}"},
+			directions.StepModifierStraight: {"einfädeln", "einfädeln auf {way_name}", "einfädeln Richtung {destination}"},
+		},
+		directions.ManeuverOnRamp: {
+			"":                              {"die Auffahrt {modifier} nehmen", "die Auffahrt {modifier} auf {way_name} nehmen", "die Auffahrt {modifier} Richtung {destination} nehmen"},
+			directions.StepModifierStraight: {"die Auffahrt nehmen", "die Auffahrt auf {way_name} nehmen", "die Auffahrt Richtung {destination} nehmen"},
+		},
+		directions.ManeuverOffRamp: {
+			"":                              {"die Ausfahrt {modifier} nehmen", "die Ausfahrt {modifier} auf {way_name} nehmen", "die Ausfahrt {modifier} Richtung {destination} nehmen"},
+			directions.StepModifierStraight: {"die Ausfahrt nehmen", "die Ausfahrt auf {way_name} nehmen", "die Ausfahrt Richtung {destination} nehmen"},
+		},
+		directions.ManeuverFork: {
+			"":                              {"an der Gabelung {modifier} halten", "an der Gabelung {modifier} halten auf {way_name}", "an der Gabelung {modifier} halten Richtung {destination}"},
+			directions.StepModifierStraight: {"an der Gabelung geradeaus halten", "an der Gabelung geradeaus halten auf {way_name}", "an der Gabelung geradeaus halten Richtung {destination}"},
+		},
+		directions.ManeuverEndOfRoad: {
+			"": {"am Ende der Straße {modifier} abbiegen", "am Ende der Straße {modifier} abbiegen auf {way_name}", "am Ende der Straße {modifier} abbiegen Richtung {destination}"},
+		},
+		directions.ManeuverRoundabout: {
+			"":           {"in den Kreisverkehr fahren", "in den Kreisverkehr fahren und auf {way_name} verlassen", "in den Kreisverkehr fahren und Richtung {destination} verlassen"},
+			ExitModifier: {"im Kreisverkehr die {nth} Ausfahrt nehmen", "im Kreisverkehr die {nth} Ausfahrt nehmen auf {way_name}", "im Kreisverkehr die {nth} Ausfahrt nehmen Richtung {destination}"},
+		},
+		directions.ManeuverRotary: {
+			"":           {"in den Kreisverkehr fahren", "in den Kreisverkehr fahren und auf {way_name} verlassen", "in den Kreisverkehr fahren und Richtung {destination} verlassen"},
+			ExitModifier: {"im Kreisverkehr die {nth} Ausfahrt nehmen", "im Kreisverkehr die {nth} Ausfahrt nehmen auf {way_name}", "im Kreisverkehr die {nth} Ausfahrt nehmen Richtung {destination}"},
+		},
+		directions.ManeuverRoundaboutTurn: {
+			"": {"am Kreisverkehr {modifier} abbiegen", "am Kreisverkehr {modifier} abbiegen auf {way_name}", "am Kreisverkehr {modifier} abbiegen Richtung {destination}"},
+		},
+		directions.ManeuverExitRoundabout: {
+			"": {"den Kreisverkehr verlassen", "den Kreisverkehr verlassen auf {way_name}", "den Kreisverkehr verlassen Richtung {destination}"},
+		},
+		directions.ManeuverExitRotary: {
+			"": {"den Kreisverkehr verlassen", "den Kreisverkehr verlassen auf {way_name}", "den Kreisverkehr verlassen Richtung {destination}"},
+		},
+	},
+	Modifiers: map[directions.StepModifier]string{
+		directions.StepModifierUTurn:       "wenden",
+		directions.StepModifierSharpRight:  "scharf rechts",
+		directions.StepModifierRight:       "rechts",
+		directions.StepModifierSlightRight: "leicht rechts",
+		directions.StepModifierStraight:    "geradeaus",
+		directions.StepModifierSharpLeft:   "scharf links",
+		directions.StepModifierLeft:        "links",
+		directions.StepModifierSlightLeft:  "leicht links",
+	},
+	Directions:       [8]string{"Norden", "Nordosten", "Osten", "Südosten", "Süden", "Südwesten", "Westen", "Nordwesten"},
+	Ordinals:         []string{"erste", "zweite", "dritte", "vierte", "fünfte", "sechste", "siebte", "achte", "neunte", "zehnte"},
+	In:               "in {distance} {instruction}",
+	Meters:           Unit{"{value} Meter", "{value} Metern"},
+	Kilometers:       Unit{"{value} Kilometer", "{value} Kilometern"},
+	Feet:             Unit{"{value} Fuß", "{value} Fuß"},
+	Miles:            Unit{"{value} Meile", "{value} Meilen"},
+	DecimalSeparator: ",",
+}
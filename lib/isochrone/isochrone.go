@@ -0,0 +1,139 @@
+/**
+ * go-mapbox Isochrone Module
+ * Wraps the mapbox isochrone API for server side use
+ * See https://docs.mapbox.com/api/navigation/isochrone/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package isochrone
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "isochrone"
+	apiVersion = "v1"
+)
+
+const (
+	// MaxContours is the maximum number of contours in a request
+	MaxContours = 4
+	// MaxMinutes is the maximum contour time in minutes
+	MaxMinutes = 60
+	// MaxMeters is the maximum contour distance in meters
+	MaxMeters = 100000
+)
+
+// RoutingProfile defines routing mode for isochrones
+type RoutingProfile string
+
+const (
+	// RoutingDrivingTraffic mode for automotive routing takes into account current and historic traffic
+	RoutingDrivingTraffic RoutingProfile = "mapbox/driving-traffic"
+	// RoutingDriving mode for for automovide routing
+	RoutingDriving RoutingProfile = "mapbox/driving"
+	// RoutingWalking mode for Pedestrian routing
+	RoutingWalking RoutingProfile = "mapbox/walking"
+	// RoutingCycling mode for bicycle routing
+	RoutingCycling RoutingProfile = "mapbox/cycling"
+)
+
+// Isochrone api wrapper instance
+type Isochrone struct {
+	base *base.Base
+}
+
+// NewIsochrone Create a new Isochrone API wrapper
+func NewIsochrone(base *base.Base) *Isochrone {
+	return &Isochrone{base}
+}
+
+// RequestOpts request options for isochrone api
+type RequestOpts struct {
+	// ContoursMinutes or ContoursMeters (but not both) set the contours in increasing order
+	ContoursMinutes []int `url:"contours_minutes,omitempty,comma"`
+	ContoursMeters  []int `url:"contours_meters,omitempty,comma"`
+	// ContoursColors are hex colors without the leading # for each contour
+	ContoursColors []string `url:"contours_colors,omitempty,comma"`
+	// Polygons returns contours as polygons rather than lines
+	Polygons bool `url:"polygons,omitempty"`
+	// Denoise removes contours smaller than this fraction (0 to 1) of the largest contour, defaults to 1 when nil
+	Denoise *float64 `url:"denoise,omitempty"`
+	// Generalize is the tolerance in meters for simplifying contours
+	Generalize float64 `url:"generalize,omitempty"`
+	// DepartAt is the departure time for driving-traffic isochrones
+	DepartAt string `url:"depart_at,omitempty"`
+}
+
+// Validate checks the contours and options are within the API limits
+func (o *RequestOpts) Validate() error {
+	if o == nil {
+		return fmt.Errorf("Isochrone request error: contours are required")
+	}
+
+	contours, limit, unit := o.ContoursMinutes, MaxMinutes, "minutes"
+	if len(o.ContoursMeters) > 0 {
+		if len(o.ContoursMinutes) > 0 {
+			return fmt.Errorf("Isochrone request error: contours_minutes and contours_meters cannot be combined")
+		}
+		contours, limit, unit = o.ContoursMeters, MaxMeters, "meters"
+	}
+
+	if len(contours) == 0 || len(contours) > MaxContours {
+		return fmt.Errorf("Isochrone request error: between 1 and %d contours are required (received %d)", MaxContours, len(contours))
+	}
+	for i, c := range contours {
+		if c <= 0 || c > limit {
+			return fmt.Errorf("Isochrone request error: contour %d must be between 1 and %d %s", c, limit, unit)
+		}
+		if i > 0 && c <= contours[i-1] {
+			return fmt.Errorf("Isochrone request error: contours must be in increasing order")
+		}
+	}
+
+	if len(o.ContoursColors) > 0 && len(o.ContoursColors) != len(contours) {
+		return fmt.Errorf("Isochrone request error: contours_colors requires %d colors", len(contours))
+	}
+	for _, c := range o.ContoursColors {
+		if strings.HasPrefix(c, "#") {
+			return fmt.Errorf("Isochrone request error: color '%s' must not include a leading #", c)
+		}
+	}
+
+	if o.Denoise != nil && (*o.Denoise < 0 || *o.Denoise > 1) {
+		return fmt.Errorf("Isochrone request error: denoise must be between 0 and 1")
+	}
+	if o.Generalize < 0 {
+		return fmt.Errorf("Isochrone request error: generalize must not be negative")
+	}
+
+	return nil
+}
+
+// GetIsochrone fetches the areas reachable from a location using the specified routing profile
+func (i *Isochrone) GetIsochrone(location base.Location, profile RoutingProfile, opts *RequestOpts) (*IsochroneResponse, error) {
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString := fmt.Sprintf("%f,%f", location.Longitude, location.Latitude)
+
+	resp := IsochroneResponse{}
+
+	err = i.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+
+	return &resp, err
+}
@@ -0,0 +1,97 @@
+/**
+ * go-mapbox Isochrone Module Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package isochrone
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const isochroneResponse = `{
+	"type": "FeatureCollection",
+	"features": [
+		{"type": "Feature", "properties": {"contour": 30, "metric": "time", "color": "6706ce", "fill-opacity": 0.33},
+		 "geometry": {"type": "Polygon", "coordinates": [[[-1, -1], [1, -1], [1, 1], [-1, 1], [-1, -1]]]}},
+		{"type": "Feature", "properties": {"contour": 15, "metric": "time", "color": "04e813"},
+		 "geometry": {"type": "Polygon", "coordinates": [
+			[[-0.5, -0.5], [0.5, -0.5], [0.5, 0.5], [-0.5, 0.5], [-0.5, -0.5]],
+			[[-0.1, -0.1], [0.1, -0.1], [0.1, 0.1], [-0.1, 0.1], [-0.1, -0.1]]
+		 ]}}
+	]
+}`
+
+func TestIsochrone(t *testing.T) {
+
+	var lastPath string
+	var lastQuery map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastPath, lastQuery = r.URL.Path, r.URL.Query()
+		w.Write([]byte(isochroneResponse))
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+	iso := NewIsochrone(b)
+
+	res, err := iso.GetIsochrone(base.Location{Latitude: 0, Longitude: 0}, RoutingDriving, &RequestOpts{
+		ContoursMinutes: []int{15, 30},
+		ContoursColors:  []string{"04e813", "6706ce"},
+		Polygons:        true,
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Builds requests", func(t *testing.T) {
+		assert.EqualValues(t, "/isochrone/v1/mapbox/driving/0.000000,0.000000", lastPath)
+		assert.EqualValues(t, []string{"15,30"}, lastQuery["contours_minutes"])
+		assert.EqualValues(t, []string{"04e813,6706ce"}, lastQuery["contours_colors"])
+		assert.EqualValues(t, []string{"true"}, lastQuery["polygons"])
+	})
+
+	t.Run("Decodes contours", func(t *testing.T) {
+		if assert.Len(t, res.Features, 2) {
+			assert.EqualValues(t, 30, res.Features[0].Properties.Contour)
+			assert.EqualValues(t, 0.33, res.Features[0].Properties.FillOpacity)
+			assert.EqualValues(t, GeometryPolygon, res.Features[1].Geometry.Type)
+			assert.Len(t, res.Features[1].Geometry.Rings, 2)
+		}
+
+		encoded, err := json.Marshal(res.Features[0].Geometry)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"type": "Polygon", "coordinates": [[[-1, -1], [1, -1], [1, 1], [-1, 1], [-1, -1]]]}`, string(encoded))
+	})
+
+	t.Run("Finds contours containing locations", func(t *testing.T) {
+		assert.EqualValues(t, 15, res.Contour(base.Location{Latitude: 0.3, Longitude: 0.3}).Properties.Contour)
+		// Holes in the inner contour fall within the outer contour
+		assert.EqualValues(t, 30, res.Contour(base.Location{Latitude: 0, Longitude: 0}).Properties.Contour)
+		assert.EqualValues(t, 30, res.Contour(base.Location{Latitude: 0.9, Longitude: -0.9}).Properties.Contour)
+		assert.Nil(t, res.Contour(base.Location{Latitude: 2, Longitude: 0}))
+	})
+
+	t.Run("Validates requests", func(t *testing.T) {
+		assert.NotNil(t, (*RequestOpts)(nil).Validate())
+		assert.NotNil(t, (&RequestOpts{ContoursMinutes: []int{30, 15}}).Validate())
+		assert.NotNil(t, (&RequestOpts{ContoursMinutes: []int{90}}).Validate())
+		assert.NotNil(t, (&RequestOpts{ContoursMinutes: []int{15}, ContoursMeters: []int{1000}}).Validate())
+		assert.NotNil(t, (&RequestOpts{ContoursMeters: []int{1000}, ContoursColors: []string{"#ff0000"}}).Validate())
+		assert.Nil(t, (&RequestOpts{ContoursMeters: []int{1000, 5000}}).Validate())
+	})
+}
@@ -0,0 +1,139 @@
+/**
+ * go-mapbox Isochrone Module Types
+ * Wraps the mapbox isochrone API for server side use
+ * See https://docs.mapbox.com/api/navigation/isochrone/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package isochrone
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// IsochroneResponse is the response from GetIsochrone, a GeoJSON feature collection of contours
+// https://docs.mapbox.com/api/navigation/isochrone/#isochrone-response-object
+type IsochroneResponse struct {
+	Type     string    `json:"type"`
+	Features []Contour `json:"features"`
+}
+
+// Contour is a GeoJSON feature for a single contour
+type Contour struct {
+	Type       string            `json:"type"`
+	Properties ContourProperties `json:"properties"`
+	Geometry   ContourGeometry   `json:"geometry"`
+}
+
+// ContourProperties are the properties of a contour
+type ContourProperties struct {
+	// Contour is the contour value in minutes or meters
+	Contour float64 `json:"contour"`
+	// Metric is time or distance
+	Metric      string  `json:"metric"`
+	Color       string  `json:"color"`
+	Opacity     float64 `json:"opacity"`
+	Fill        string  `json:"fill"`
+	FillOpacity float64 `json:"fill-opacity"`
+	FillColor   string  `json:"fillColor"`
+}
+
+// Geometry types for contours
+const (
+	GeometryPolygon    = "Polygon"
+	GeometryLineString = "LineString"
+)
+
+// ContourGeometry is a GeoJSON Polygon (when requested with polygons) or LineString geometry
+type ContourGeometry struct {
+	Type string
+	// Rings are the polygon rings, outer ring first then holes, or the single line of a LineString
+	Rings [][]base.Point
+}
+
+// UnmarshalJSON decodes GeoJSON Polygon and LineString geometries
+func (g *ContourGeometry) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	g.Type = raw.Type
+	switch raw.Type {
+	case GeometryPolygon:
+		return json.Unmarshal(raw.Coordinates, &g.Rings)
+	case GeometryLineString:
+		line := []base.Point{}
+		if err := json.Unmarshal(raw.Coordinates, &line); err != nil {
+			return err
+		}
+		g.Rings = [][]base.Point{line}
+		return nil
+	default:
+		return fmt.Errorf("Unsupported contour geometry type: %s", raw.Type)
+	}
+}
+
+// MarshalJSON encodes the geometry as GeoJSON
+func (g ContourGeometry) MarshalJSON() ([]byte, error) {
+	var coordinates interface{} = g.Rings
+	if g.Type == GeometryLineString && len(g.Rings) > 0 {
+		coordinates = g.Rings[0]
+	}
+	return json.Marshal(struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}{g.Type, coordinates})
+}
+
+// Contains checks whether a location falls inside the contour, being inside the outer ring and
+// outside any holes. LineString contours are treated as closed rings.
+func (c *Contour) Contains(loc base.Location) bool {
+	rings := c.Geometry.Rings
+	if len(rings) == 0 || !ringContains(rings[0], loc) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if ringContains(hole, loc) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contour finds the smallest contour containing a location, returning nil if the location is
+// outside all contours
+func (r *IsochroneResponse) Contour(loc base.Location) *Contour {
+	var smallest *Contour
+	for i := range r.Features {
+		c := &r.Features[i]
+		if c.Contains(loc) && (smallest == nil || c.Properties.Contour < smallest.Properties.Contour) {
+			smallest = c
+		}
+	}
+	return smallest
+}
+
+// ringContains tests whether a ring of [lng, lat] points contains a location using ray casting
+func ringContains(ring []base.Point, loc base.Location) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if len(a) < 2 || len(b) < 2 {
+			continue
+		}
+		if (a[1] > loc.Latitude) != (b[1] > loc.Latitude) &&
+			loc.Longitude < (b[0]-a[0])*(loc.Latitude-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
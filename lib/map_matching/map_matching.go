@@ -22,6 +22,12 @@ const (
 	apiVersion = "v5"
 )
 
+// osrmService is the equivalent OSRM service for self hosted backends
+var osrmService = base.OSRMService{
+	Name:   "match",
+	Params: []string{"geometries", "radiuses", "steps", "overview", "timestamps", "annotations", "bearings", "gaps", "tidy", "waypoints"},
+}
+
 // MapMatching api wrapper instance
 type MapMatching struct {
 	base *base.Base
@@ -72,7 +78,8 @@ func (o *RequestOpts) SetAnnotations(annotations []AnnotationType) {
 	for i, a := range annotations {
 		lines[i] = fmt.Sprintf("%s", a)
 	}
-	o.Radiuses = strings.Join(lines, ",")
+	joined := AnnotationType(strings.Join(lines, ","))
+	o.Annotations = &joined
 }
 
 // SetTimestamps builds the Timestamps query argument from an array of timestamps types
@@ -116,7 +123,7 @@ func (d *MapMatching) GetMatching(path []base.Location, profile RoutingProfile,
 
 	resp := MatchingResponse{}
 
-	err = d.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+	err = d.base.QueryRouting(apiName, apiVersion, osrmService, string(profile), queryString, &v, &resp)
 
 	return &resp, err
 }
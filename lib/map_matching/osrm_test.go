@@ -0,0 +1,50 @@
+/**
+ * go-mapbox Map Matching Module OSRM Backend Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package mapmatching
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestOSRMMatching(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/match/v1/driving/-122.442541,37.753196;-122.442380,37.753738", r.URL.Path)
+		w.Write([]byte(`{"code":"Ok","matchings":[{"confidence":0.9,"distance":60.5,"duration":8.1,"geometry":"_p~iF~ps|U_ulLnnqC","legs":[{"summary":"","duration":8.1,"distance":60.5}]}],` +
+			`"tracepoints":[{"waypoint_index":0,"matchings_index":0,"location":[-122.442541,37.753196],"name":"Market Street"},null]}`))
+	}))
+	defer server.Close()
+
+	mapMatching := NewMapMaptching(base.NewOSRMBase(server.URL))
+
+	locs := []base.Location{{Latitude: 37.75319556403746, Longitude: -122.44254112243651}, {Latitude: 37.75373846204306, Longitude: -122.44238018989562}}
+
+	t.Run("Decodes OSRM match responses", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetGeometries(GeometryPolyline)
+
+		res, err := mapMatching.GetMatching(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.Equal(t, CodeOK, Codes(res.Code))
+		if assert.Len(t, res.Matchings, 1) {
+			assert.Equal(t, 0.9, res.Matchings[0].Confidence)
+			path, err := res.Matchings[0].GetPath(GeometryPolyline)
+			assert.Nil(t, err)
+			assert.Len(t, path, 2)
+		}
+		if assert.Len(t, res.Tracepoint, 2) {
+			assert.Equal(t, "Market Street", res.Tracepoint[0].Name)
+		}
+	})
+}
@@ -11,6 +11,8 @@ package mapmatching
 
 import (
 	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
 )
 
 // MatchingResponse is the response from GetMatching
@@ -18,7 +20,7 @@ import (
 type MatchingResponse struct {
 	Code       string
 	Matchings  []Matchings
-	Tracepoint []TracePoint
+	Tracepoint []TracePoint `json:"tracepoints"`
 }
 
 type Coordinate []float64
@@ -92,6 +94,34 @@ func (m *Matchings) GetGeometryPolyline() (string, error) {
 	return g, nil
 }
 
+// GetPath decodes the matching geometry into a path using the geometry type the matching was requested with
+func (m *Matchings) GetPath(geometry GeometryType) ([]base.Location, error) {
+	switch geometry {
+	case GeometryGeojson:
+		g, err := m.GetGeometryGeojson()
+		if err != nil {
+			return nil, err
+		}
+		path := make([]base.Location, len(g.Coordinates))
+		for i, c := range g.Coordinates {
+			path[i] = base.PointToLocation(base.Point(c))
+		}
+		return path, nil
+	case "", GeometryPolyline, GeometryPolyline6:
+		g, err := m.GetGeometryPolyline()
+		if err != nil {
+			return nil, err
+		}
+		precision := base.PolylinePrecision5
+		if geometry == GeometryPolyline6 {
+			precision = base.PolylinePrecision6
+		}
+		return base.DecodePolyline(g, precision)
+	default:
+		return nil, fmt.Errorf("Unsupported geometry type: %s", geometry)
+	}
+}
+
 //MatchingLeg legs inside the matching object
 type MatchingLeg struct {
 	Step     []float64
@@ -102,10 +132,10 @@ type MatchingLeg struct {
 
 // TracePoint represents the location an input point was matched with
 type TracePoint struct {
-	WaypointIndex  int16
+	WaypointIndex  int16 `json:"waypoint_index"`
 	Location       []float64
 	Name           string
-	MatchingsIndex int16
+	MatchingsIndex int16 `json:"matchings_index"`
 }
 
 // OverviewType Type of returned overview geometry
@@ -14,8 +14,11 @@ import (
 	"github.com/ryankurte/go-mapbox/lib/directions"
 	"github.com/ryankurte/go-mapbox/lib/directions_matrix"
 	"github.com/ryankurte/go-mapbox/lib/geocode"
+	"github.com/ryankurte/go-mapbox/lib/isochrone"
 	"github.com/ryankurte/go-mapbox/lib/map_matching"
 	"github.com/ryankurte/go-mapbox/lib/maps"
+	"github.com/ryankurte/go-mapbox/lib/optimization"
+	"github.com/ryankurte/go-mapbox/lib/searchbox"
 )
 
 // Mapbox API Wrapper structure
@@ -31,6 +34,12 @@ type Mapbox struct {
 	DirectionsMatrix *directionsmatrix.DirectionsMatrix
 	// MapMatching snaps inaccurate path tracked to a map to produce a clean path
 	MapMatching *mapmatching.MapMatching
+	// Isochrone returns the areas reachable from a location within times or distances
+	Isochrone *isochrone.Isochrone
+	// Optimization finds the optimal order to visit a set of locations
+	Optimization *optimization.Optimization
+	// SearchBox provides interactive (type-ahead) search for places and points of interest
+	SearchBox *searchbox.SearchBox
 }
 
 // NewMapbox Create a new mapbox API instance
@@ -50,6 +59,9 @@ func NewMapbox(token string) (*Mapbox, error) {
 	m.Directions = directions.NewDirections(m.base)
 	m.DirectionsMatrix = directionsmatrix.NewDirectionsMatrix(m.base)
 	m.MapMatching = mapmatching.NewMapMaptching(m.base)
+	m.Isochrone = isochrone.NewIsochrone(m.base)
+	m.Optimization = optimization.NewOptimization(m.base)
+	m.SearchBox = searchbox.NewSearchBox(m.base)
 
 	return m, nil
 }
@@ -67,10 +67,7 @@ func TestMaps(t *testing.T) {
 	// Directions Matrix API
 	var directionMatrixOpts directionsmatrix.RequestOpts
 	// Only 1st and second points will act as a source the response will be a 2x3 matrix
-	source := []string{"0", "1"}
-	dest := []string{"all"}
-	directionMatrixOpts.SetSources(source)
-	directionMatrixOpts.SetDestinations(dest)
+	directionMatrixOpts.SetSources([]int{0, 1})
 
 	points := []base.Location{{37.752759, -122.467600}, {37.762819, -122.460304}, {37.758095, -122.442253}}
 
@@ -0,0 +1,104 @@
+/**
+ * go-mapbox Navigation Module Fix Export
+ * Exports fixes as GPX tracks or submits them to the map matching API
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package navigation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/map_matching"
+)
+
+const (
+	// DefaultMatchingRadius is the map matching radius in meters used for fixes without an accuracy
+	DefaultMatchingRadius = 5
+	// MaxMatchingRadius is the maximum map matching radius in meters
+	MaxMatchingRadius = 50
+	// MaxMatchingCoordinates is the maximum number of fixes in a map matching request
+	MaxMatchingCoordinates = 100
+)
+
+type gpx struct {
+	XMLName xml.Name `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version string   `xml:"version,attr"`
+	Creator string   `xml:"creator,attr"`
+	Track   gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name    string     `xml:"name,omitempty"`
+	Segment []gpxPoint `xml:"trkseg>trkpt"`
+}
+
+type gpxPoint struct {
+	Latitude  float64 `xml:"lat,attr"`
+	Longitude float64 `xml:"lon,attr"`
+	Time      string  `xml:"time"`
+}
+
+// WriteGPX writes fixes as a GPX 1.1 track
+func WriteGPX(w io.Writer, name string, fixes []Fix) error {
+	doc := gpx{Version: "1.1", Creator: "go-mapbox", Track: gpxTrack{Name: name}}
+	for _, f := range fixes {
+		doc.Track.Segment = append(doc.Track.Segment, gpxPoint{
+			Latitude:  f.Location.Latitude,
+			Longitude: f.Location.Longitude,
+			Time:      f.Time.UTC().Format(time.RFC3339Nano),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// MatchFixes map matches fixes, setting timestamps and radiuses (from fix accuracies) on a copy of the options
+// Traces longer than MaxMatchingCoordinates are rejected and must be split into multiple requests
+func MatchFixes(m *mapmatching.MapMatching, fixes []Fix, profile mapmatching.RoutingProfile, opts *mapmatching.RequestOpts) (*mapmatching.MatchingResponse, error) {
+	if len(fixes) > MaxMatchingCoordinates {
+		return nil, fmt.Errorf("Map matching error: at most %d fixes can be matched in a request (received %d)", MaxMatchingCoordinates, len(fixes))
+	}
+
+	o := mapmatching.RequestOpts{}
+	if opts != nil {
+		o = *opts
+	}
+
+	path := make([]base.Location, len(fixes))
+	timestamps := make([]int64, len(fixes))
+	radiuses := make([]int, len(fixes))
+	hasAccuracy := false
+	for i, f := range fixes {
+		path[i] = f.Location
+		timestamps[i] = f.Time.Unix()
+		radiuses[i] = DefaultMatchingRadius
+		if f.Accuracy > 0 {
+			hasAccuracy = true
+			radiuses[i] = int(math.Min(MaxMatchingRadius, math.Ceil(f.Accuracy)))
+		}
+	}
+
+	o.SetTimestamps(timestamps)
+	if hasAccuracy {
+		o.SetRadiuses(radiuses)
+	}
+
+	return m.GetMatching(path, profile, &o)
+}
@@ -0,0 +1,240 @@
+/**
+ * go-mapbox Navigation Module Simulator
+ * Generates synthetic GPS fixes along a route for testing tracking and arrival estimation
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package navigation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+// DefaultInterval is the default time between simulated fixes
+const DefaultInterval = time.Second
+
+// SimulatorOpts options for route simulation
+type SimulatorOpts struct {
+	// Geometry is the geometry type the route was requested with
+	Geometry directions.GeometryType
+	// Start is the time of the first fix
+	Start time.Time
+	// SpeedFactor scales travel speed, where 2 travels the route in half the expected duration
+	SpeedFactor float64
+	// Interval is the time between fixes
+	Interval time.Duration
+	// Noise is the standard deviation in meters of the position error added to fixes
+	Noise float64
+	// Dropout is the probability (0 to 1) of each fix being dropped
+	Dropout float64
+	// Seed seeds noise and dropouts, so fixes are reproducible for a given seed
+	Seed int64
+}
+
+// simSegment is a section of the route path with its travel time in seconds
+type simSegment struct {
+	a, b     base.Location
+	duration float64
+}
+
+// Simulator generates timestamped GPS fixes along a route
+type Simulator struct {
+	opts     SimulatorOpts
+	segments []simSegment
+	duration float64
+	rand     *rand.Rand
+
+	elapsed time.Duration
+	index   int
+	offset  float64
+	done    bool
+}
+
+// NewSimulator creates a simulator for a route
+// Travel times use the route overview geometry with duration annotations where they match the geometry,
+// otherwise step geometries with step durations, otherwise the overview geometry at the route average speed
+func NewSimulator(route *directions.Route, opts *SimulatorOpts) (*Simulator, error) {
+	o := SimulatorOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.SpeedFactor <= 0 {
+		o.SpeedFactor = 1
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	if o.Dropout < 0 || o.Dropout >= 1 {
+		return nil, fmt.Errorf("Simulator dropout must be in the range [0, 1)")
+	}
+
+	segments, err := simSegments(route, o.Geometry)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("Route has no geometry to simulate")
+	}
+
+	s := &Simulator{opts: o, segments: segments, rand: rand.New(rand.NewSource(o.Seed))}
+	for i := range s.segments {
+		s.segments[i].duration /= o.SpeedFactor
+		s.duration += s.segments[i].duration
+	}
+
+	return s, nil
+}
+
+// Duration returns the simulated travel time of the route
+func (s *Simulator) Duration() time.Duration {
+	return time.Duration(s.duration * float64(time.Second))
+}
+
+// Next returns the next fix, false once the end of the route has been reached
+func (s *Simulator) Next() (Fix, bool) {
+	for !s.done {
+		t := s.elapsed.Seconds()
+		if t >= s.duration {
+			// Always finish with a fix at the end of the route
+			t, s.done = s.duration, true
+		}
+		s.elapsed += s.opts.Interval
+
+		loc := s.locationAt(t)
+		if s.opts.Dropout > 0 && s.rand.Float64() < s.opts.Dropout {
+			continue
+		}
+
+		fix := Fix{
+			Location: s.addNoise(loc),
+			Time:     s.opts.Start.Add(time.Duration(t * float64(time.Second))),
+			Accuracy: s.opts.Noise,
+		}
+		return fix, true
+	}
+	return Fix{}, false
+}
+
+// Fixes returns all remaining fixes
+func (s *Simulator) Fixes() []Fix {
+	fixes := []Fix{}
+	for f, ok := s.Next(); ok; f, ok = s.Next() {
+		fixes = append(fixes, f)
+	}
+	return fixes
+}
+
+// Stream returns a channel that emits all remaining fixes and is then closed
+// Streaming stops and the channel is closed early when the context is cancelled
+func (s *Simulator) Stream(ctx context.Context) <-chan Fix {
+	ch := make(chan Fix)
+	go func() {
+		defer close(ch)
+		for f, ok := s.Next(); ok && ctx.Err() == nil; f, ok = s.Next() {
+			select {
+			case ch <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// locationAt finds the location at a time in seconds, which must not decrease between calls
+func (s *Simulator) locationAt(t float64) base.Location {
+	for s.index < len(s.segments)-1 && t > s.offset+s.segments[s.index].duration {
+		s.offset += s.segments[s.index].duration
+		s.index++
+	}
+
+	seg := s.segments[s.index]
+	if seg.duration <= 0 {
+		return seg.b
+	}
+	return base.Interpolate(seg.a, seg.b, math.Min(1, (t-s.offset)/seg.duration))
+}
+
+// addNoise offsets a location by a normally distributed error
+func (s *Simulator) addNoise(loc base.Location) base.Location {
+	if s.opts.Noise <= 0 {
+		return loc
+	}
+	north := s.rand.NormFloat64() * s.opts.Noise
+	east := s.rand.NormFloat64() * s.opts.Noise
+
+	lat := loc.Latitude * math.Pi / 180
+	return base.Location{
+		Latitude:  loc.Latitude + north/base.EarthRadius*180/math.Pi,
+		Longitude: loc.Longitude + east/(base.EarthRadius*math.Cos(lat))*180/math.Pi,
+	}
+}
+
+// simSegments builds timed segments for a route
+func simSegments(route *directions.Route, geometry directions.GeometryType) ([]simSegment, error) {
+	var path []base.Location
+	if route.Geometry != "" {
+		var err error
+		if path, err = route.GetPath(geometry); err != nil {
+			return nil, err
+		}
+	}
+
+	// Annotation durations for each segment of the overview geometry
+	durations := []float64{}
+	for _, leg := range route.Legs {
+		durations = append(durations, leg.Annotation.Duration...)
+	}
+	if len(path) > 1 && len(durations) == len(path)-1 {
+		segments := make([]simSegment, len(durations))
+		for i, d := range durations {
+			segments[i] = simSegment{path[i], path[i+1], d}
+		}
+		return segments, nil
+	}
+
+	// Step geometries with step durations
+	segments := []simSegment{}
+	for l := range route.Legs {
+		for s := range route.Legs[l].Steps {
+			step := &route.Legs[l].Steps[s]
+			if step.Geometry == "" {
+				continue
+			}
+			stepPath, err := step.GetPath(geometry)
+			if err != nil {
+				return nil, fmt.Errorf("Error decoding step %d of leg %d: %s", s, l, err)
+			}
+			segments = append(segments, timedSegments(stepPath, step.Duration)...)
+		}
+	}
+	if len(segments) > 0 {
+		return segments, nil
+	}
+
+	// Overview geometry at the average speed
+	return timedSegments(path, route.Duration), nil
+}
+
+// timedSegments divides a duration between the segments of a path by distance
+func timedSegments(path []base.Location, duration float64) []simSegment {
+	length := base.PathLength(path)
+	segments := []simSegment{}
+	for i := 1; i < len(path); i++ {
+		d := 0.0
+		if length > 0 {
+			d = duration * base.Distance(path[i-1], path[i]) / length
+		}
+		segments = append(segments, simSegment{path[i-1], path[i], d})
+	}
+	return segments
+}
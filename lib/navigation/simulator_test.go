@@ -0,0 +1,162 @@
+/**
+ * go-mapbox Navigation Module Simulator Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package navigation
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+	"github.com/ryankurte/go-mapbox/lib/map_matching"
+)
+
+func TestSimulator(t *testing.T) {
+	now := time.Date(2019, 5, 2, 15, 0, 0, 0, time.UTC)
+
+	t.Run("Requires route geometry", func(t *testing.T) {
+		_, err := NewSimulator(&directions.Route{}, nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Follows step durations", func(t *testing.T) {
+		sim, err := NewSimulator(testRoute(), &SimulatorOpts{Geometry: directions.GeometryPolyline6, Start: now, Interval: 10 * time.Second})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, 200*time.Second, sim.Duration())
+
+		fixes := sim.Fixes()
+		if assert.Len(t, fixes, 21) {
+			assert.Equal(t, now, fixes[0].Time)
+			assert.InDelta(t, 0, base.Distance(start, fixes[0].Location), 1)
+			assert.InDelta(t, 0, base.Distance(corner, fixes[10].Location), 1)
+			assert.InDelta(t, 0, base.Distance(end, fixes[20].Location), 1)
+			assert.Equal(t, now.Add(200*time.Second), fixes[20].Time)
+		}
+
+		_, ok := sim.Next()
+		assert.False(t, ok)
+	})
+
+	t.Run("Uses annotation durations for the overview geometry", func(t *testing.T) {
+		route := testRoute()
+		route.Geometry = base.EncodePolyline([]base.Location{start, corner, end}, base.PolylinePrecision6)
+		route.Legs[0].Annotation.Duration = []float64{20, 80}
+
+		sim, err := NewSimulator(route, &SimulatorOpts{Geometry: directions.GeometryPolyline6, Start: now, Interval: 10 * time.Second, SpeedFactor: 2})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, 50*time.Second, sim.Duration())
+
+		fixes := sim.Fixes()
+		if assert.Len(t, fixes, 6) {
+			assert.InDelta(t, 0, base.Distance(corner, fixes[1].Location), 1)
+			assert.Equal(t, now.Add(50*time.Second), fixes[5].Time)
+		}
+	})
+
+	t.Run("Adds noise and dropouts reproducibly", func(t *testing.T) {
+		opts := &SimulatorOpts{Geometry: directions.GeometryPolyline6, Start: now, Noise: 10, Dropout: 0.5, Seed: 7}
+
+		sim, _ := NewSimulator(testRoute(), opts)
+		fixes := sim.Fixes()
+		assert.True(t, len(fixes) > 50 && len(fixes) < 150)
+
+		tracker, _ := NewTracker(testRoute(), &TrackerOpts{Geometry: directions.GeometryPolyline6})
+		for _, f := range fixes {
+			assert.Equal(t, 10.0, f.Accuracy)
+			p := tracker.Update(f)
+			assert.True(t, p.DistanceFromRoute < 60)
+		}
+
+		again, _ := NewSimulator(testRoute(), opts)
+		assert.Equal(t, fixes, again.Fixes())
+
+		_, err := NewSimulator(testRoute(), &SimulatorOpts{Dropout: 1})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Streams fixes", func(t *testing.T) {
+		sim, _ := NewSimulator(testRoute(), &SimulatorOpts{Geometry: directions.GeometryPolyline6, Interval: 50 * time.Second})
+		count := 0
+		for range sim.Stream(context.Background()) {
+			count++
+		}
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("Stops streaming when cancelled", func(t *testing.T) {
+		sim, _ := NewSimulator(testRoute(), &SimulatorOpts{Geometry: directions.GeometryPolyline6, Interval: time.Second})
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := sim.Stream(ctx)
+
+		<-stream
+		cancel()
+
+		// At most one pending fix is delivered after cancellation
+		remaining := 0
+		for range stream {
+			remaining++
+		}
+		assert.True(t, remaining <= 1)
+	})
+
+	t.Run("Writes GPX tracks", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		fixes := []Fix{{Location: start, Time: now}, {Location: corner, Time: now.Add(time.Second)}}
+
+		err := WriteGPX(&buf, "test", fixes)
+		assert.Nil(t, err)
+		gpx := buf.String()
+		assert.True(t, strings.HasPrefix(gpx, "<?xml"))
+		assert.Contains(t, gpx, `<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="go-mapbox">`)
+		assert.Contains(t, gpx, `<trkpt lat="38.9" lon="-76.99">`)
+		assert.Contains(t, gpx, `<time>2019-05-02T15:00:01Z</time>`)
+	})
+
+	t.Run("Map matches fixes", func(t *testing.T) {
+		queries := make(chan map[string][]string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries <- r.URL.Query()
+			w.Write([]byte(`{"code":"Ok"}`))
+		}))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+
+		fixes := []Fix{{Location: start, Time: now, Accuracy: 7.5}, {Location: corner, Time: now.Add(time.Second), Accuracy: 80}, {Location: end, Time: now.Add(2 * time.Second)}}
+		resp, err := MatchFixes(mapmatching.NewMapMaptching(b), fixes, mapmatching.RoutingDriving, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "Ok", resp.Code)
+
+		q := <-queries
+		assert.Equal(t, []string{"8;50;5"}, q["radiuses"])
+		assert.Equal(t, []string{"1556809200;1556809201;1556809202"}, q["timestamps"])
+	})
+
+	t.Run("Rejects traces over the coordinate limit", func(t *testing.T) {
+		b, _ := base.NewBase("test-token")
+		fixes := make([]Fix, MaxMatchingCoordinates+1)
+		for i := range fixes {
+			fixes[i] = Fix{Location: start, Time: now.Add(time.Duration(i) * time.Second)}
+		}
+
+		_, err := MatchFixes(mapmatching.NewMapMaptching(b), fixes, mapmatching.RoutingDriving, nil)
+		assert.NotNil(t, err)
+	})
+}
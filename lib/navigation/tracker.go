@@ -0,0 +1,293 @@
+/**
+ * go-mapbox Navigation Module Progress Tracker
+ * Tracks progress along a route from GPS fixes and detects when a vehicle leaves the route
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package navigation
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+const (
+	// DefaultTolerance is the default distance in meters a fix can be from the route before it is off-route
+	DefaultTolerance = 50.0
+	// DefaultDwell is the default time fixes must remain off-route before an off-route event is emitted
+	DefaultDwell = 5 * time.Second
+	// DefaultArrivalDistance is the default distance in meters from the end of the route considered arrived
+	DefaultArrivalDistance = 20.0
+)
+
+// TrackerOpts options for route progress tracking
+type TrackerOpts struct {
+	// Geometry is the geometry type the route was requested with
+	Geometry directions.GeometryType
+	// Tolerance is the distance in meters a fix (plus its accuracy) can be from the route before it is off-route
+	Tolerance float64
+	// Dwell is the time fixes must remain off-route before an off-route event is emitted, avoiding
+	// reroutes due to brief GPS errors. A negative dwell emits events on the first off-route fix
+	Dwell time.Duration
+	// ArrivalDistance is the distance in meters from the end of the route considered arrived
+	ArrivalDistance float64
+}
+
+// Fix is a GPS position report
+type Fix struct {
+	Location base.Location
+	Time     time.Time
+	// Accuracy is the horizontal accuracy in meters, if known
+	Accuracy float64
+}
+
+// EventType is a route progress event
+type EventType string
+
+const (
+	// EventStepChanged is emitted when the current step changes
+	EventStepChanged EventType = "step_changed"
+	// EventOffRoute is emitted when fixes have been off the route for the dwell time, indicating a reroute is required
+	EventOffRoute EventType = "off_route"
+	// EventRejoined is emitted when fixes return to the route after an off-route event
+	EventRejoined EventType = "rejoined"
+	// EventArrived is emitted when the end of the route is reached
+	EventArrived EventType = "arrived"
+)
+
+// Progress is the position of a vehicle along a route
+type Progress struct {
+	Fix Fix
+	// Snapped is the nearest location on the route
+	Snapped base.Location
+	// DistanceFromRoute is the distance in meters from the fix to the route
+	DistanceFromRoute float64
+	// DistanceTraveled and DistanceRemaining along the route in meters
+	DistanceTraveled  float64
+	DistanceRemaining float64
+	// DurationRemaining is the estimated remaining travel time
+	DurationRemaining time.Duration
+	// LegIndex and StepIndex identify the current step
+	LegIndex, StepIndex int
+	Step                *directions.RouteStep
+	// NextStep is the upcoming step (and maneuver), nil on the final step
+	NextStep *directions.RouteStep
+	// DistanceToManeuver is the distance in meters to the next maneuver
+	DistanceToManeuver float64
+	// OffRoute indicates an off-route event has been emitted and the vehicle has not rejoined the route
+	OffRoute bool
+	// Events are the events triggered by this fix
+	Events []EventType
+}
+
+// segment is a section of the route path between two locations within a step
+type segment struct {
+	a, b       base.Location
+	start, end float64
+	leg, step  int
+}
+
+// stepRange is the section of the route path covered by a step
+type stepRange struct {
+	start, end float64
+}
+
+// Tracker tracks progress along a route
+type Tracker struct {
+	route    *directions.Route
+	opts     TrackerOpts
+	segments []segment
+	steps    [][]stepRange
+	legs     []stepRange
+	length   float64
+
+	traveled   float64
+	leg, step  int
+	offSince   time.Time
+	offRoute   bool
+	arrived    bool
+	hasStarted bool
+}
+
+// NewTracker creates a progress tracker for a route, requested with steps enabled
+func NewTracker(route *directions.Route, opts *TrackerOpts) (*Tracker, error) {
+	o := TrackerOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = DefaultTolerance
+	}
+	if o.Dwell == 0 {
+		o.Dwell = DefaultDwell
+	} else if o.Dwell < 0 {
+		o.Dwell = 0
+	}
+	if o.ArrivalDistance <= 0 {
+		o.ArrivalDistance = DefaultArrivalDistance
+	}
+
+	t := &Tracker{route: route, opts: o}
+
+	for l := range route.Legs {
+		leg := &route.Legs[l]
+		legStart := t.length
+		steps := make([]stepRange, len(leg.Steps))
+
+		for s := range leg.Steps {
+			path, err := leg.Steps[s].GetPath(o.Geometry)
+			if err != nil {
+				return nil, fmt.Errorf("Error decoding step %d of leg %d: %s", s, l, err)
+			}
+
+			steps[s].start = t.length
+			for i := 1; i < len(path); i++ {
+				d := base.Distance(path[i-1], path[i])
+				t.segments = append(t.segments, segment{path[i-1], path[i], t.length, t.length + d, l, s})
+				t.length += d
+			}
+			steps[s].end = t.length
+		}
+
+		t.steps = append(t.steps, steps)
+		t.legs = append(t.legs, stepRange{legStart, t.length})
+	}
+
+	if len(t.segments) == 0 {
+		return nil, fmt.Errorf("Route has no step geometry, directions must be requested with steps enabled")
+	}
+
+	return t, nil
+}
+
+// Update updates the tracker with a new fix, returning the progress along the route and any events
+// Fixes that cannot be matched to the route (eg. with NaN coordinates) are treated as off the route
+func (t *Tracker) Update(fix Fix) *Progress {
+	// Find the nearest segment, not moving backwards along the route by more than the tolerance
+	// so routes that overlap themselves are tracked in order. Ties at step boundaries select the later step
+	best, bestDistance, bestLocation, bestFraction := -1, math.Inf(1), base.Location{}, 0.0
+	for i, s := range t.segments {
+		if s.end < t.traveled-t.opts.Tolerance {
+			continue
+		}
+		loc, f := base.NearestOnSegment(fix.Location, s.a, s.b)
+		if d := base.Distance(fix.Location, loc); d <= bestDistance {
+			best, bestDistance, bestLocation, bestFraction = i, d, loc, f
+		}
+	}
+
+	p := &Progress{
+		Fix:               fix,
+		Snapped:           bestLocation,
+		DistanceFromRoute: bestDistance,
+	}
+
+	// Update position and events
+	onRoute := best >= 0 && bestDistance <= t.opts.Tolerance+fix.Accuracy
+	if onRoute {
+		s := t.segments[best]
+		t.offSince = time.Time{}
+		if t.offRoute {
+			t.offRoute = false
+			p.Events = append(p.Events, EventRejoined)
+		}
+
+		traveled := s.start + (s.end-s.start)*bestFraction
+		t.traveled = math.Max(t.traveled, traveled)
+
+		advanced := s.leg > t.leg || (s.leg == t.leg && s.step > t.step)
+		if t.hasStarted && advanced {
+			p.Events = append(p.Events, EventStepChanged)
+		}
+		if !t.hasStarted || advanced {
+			t.leg, t.step = s.leg, s.step
+		}
+		t.hasStarted = true
+	} else if !t.offRoute {
+		if t.offSince.IsZero() {
+			t.offSince = fix.Time
+		}
+		if fix.Time.Sub(t.offSince) >= t.opts.Dwell {
+			t.offRoute = true
+			p.Events = append(p.Events, EventOffRoute)
+		}
+	}
+
+	if !t.arrived && onRoute && t.length-t.traveled <= t.opts.ArrivalDistance {
+		t.arrived = true
+		p.Events = append(p.Events, EventArrived)
+	}
+
+	p.OffRoute = t.offRoute
+	p.DistanceTraveled = t.traveled
+	p.DistanceRemaining = math.Max(0, t.length-t.traveled)
+	p.LegIndex, p.StepIndex = t.leg, t.step
+	p.Step = &t.route.Legs[t.leg].Steps[t.step]
+	p.NextStep = t.nextStep()
+	p.DistanceToManeuver = math.Max(0, t.steps[t.leg][t.step].end-t.traveled)
+	p.DurationRemaining = t.durationRemaining()
+
+	return p
+}
+
+func (t *Tracker) nextStep() *directions.RouteStep {
+	if t.step+1 < len(t.route.Legs[t.leg].Steps) {
+		return &t.route.Legs[t.leg].Steps[t.step+1]
+	}
+	if t.leg+1 < len(t.route.Legs) && len(t.route.Legs[t.leg+1].Steps) > 0 {
+		return &t.route.Legs[t.leg+1].Steps[0]
+	}
+	return nil
+}
+
+// durationRemaining estimates the remaining duration using the current leg annotations where available,
+// otherwise the current leg step durations, plus the durations of following legs
+func (t *Tracker) durationRemaining() time.Duration {
+	leg := &t.route.Legs[t.leg]
+	legRange := t.legs[t.leg]
+
+	fraction := 1.0
+	if length := legRange.end - legRange.start; length > 0 {
+		fraction = math.Max(0, math.Min(1, (t.traveled-legRange.start)/length))
+	}
+
+	remaining := 0.0
+	a := leg.Annotation
+	if len(a.Duration) > 0 && len(a.Duration) == len(a.Distance) {
+		total := 0.0
+		for _, d := range a.Distance {
+			total += d
+		}
+		position := fraction * total
+		for i, d := range a.Distance {
+			if position <= 0 {
+				remaining += a.Duration[i]
+			} else if position < d {
+				remaining += a.Duration[i] * (1 - position/d)
+			}
+			position -= d
+		}
+	} else {
+		stepRange := t.steps[t.leg][t.step]
+		stepFraction := 1.0
+		if length := stepRange.end - stepRange.start; length > 0 {
+			stepFraction = math.Max(0, math.Min(1, (t.traveled-stepRange.start)/length))
+		}
+		remaining = leg.Steps[t.step].Duration * (1 - stepFraction)
+		for _, s := range leg.Steps[t.step+1:] {
+			remaining += s.Duration
+		}
+	}
+
+	for _, l := range t.route.Legs[t.leg+1:] {
+		remaining += l.Duration
+	}
+
+	return time.Duration(remaining * float64(time.Second))
+}
@@ -0,0 +1,146 @@
+/**
+ * go-mapbox Navigation Module Progress Tracker Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package navigation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+var (
+	start  = base.Location{Latitude: 38.90, Longitude: -77.00}
+	corner = base.Location{Latitude: 38.90, Longitude: -76.99}
+	end    = base.Location{Latitude: 38.91, Longitude: -76.99}
+)
+
+// testRoute builds a route heading east then turning north
+func testRoute() *directions.Route {
+	encode := func(path ...base.Location) string {
+		return base.EncodePolyline(path, base.PolylinePrecision6)
+	}
+
+	return &directions.Route{
+		Distance: 1978,
+		Duration: 200,
+		Legs: []directions.RouteLeg{{
+			Distance: 1978,
+			Duration: 200,
+			Steps: []directions.RouteStep{
+				{Distance: 866, Duration: 100, Geometry: encode(start, corner), Maneuver: directions.StepManeuver{Type: directions.ManeuverDepart}},
+				{Distance: 1112, Duration: 100, Geometry: encode(corner, end), Maneuver: directions.StepManeuver{Type: directions.ManeuverTurn, Modifier: directions.StepModifierLeft}},
+				{Geometry: encode(end, end), Maneuver: directions.StepManeuver{Type: directions.ManeuverArrive}},
+			},
+		}},
+	}
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2019, 5, 2, 15, 0, 0, 0, time.UTC)
+
+	t.Run("Requires step geometry", func(t *testing.T) {
+		_, err := NewTracker(&directions.Route{Legs: []directions.RouteLeg{{}}}, nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Tracks progress along a route", func(t *testing.T) {
+		tracker, err := NewTracker(testRoute(), &TrackerOpts{Geometry: directions.GeometryPolyline6})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		// Halfway along the first step, slightly north of the road
+		p := tracker.Update(Fix{Location: base.Location{Latitude: 38.9001, Longitude: -76.995}, Time: now})
+		assert.InDelta(t, 11, p.DistanceFromRoute, 1)
+		assert.InDelta(t, 433, p.DistanceTraveled, 5)
+		assert.InDelta(t, 433, p.DistanceToManeuver, 5)
+		assert.InDelta(t, 1545, p.DistanceRemaining, 10)
+		assert.InDelta(t, 150, p.DurationRemaining.Seconds(), 1)
+		assert.EqualValues(t, 0, p.StepIndex)
+		assert.EqualValues(t, directions.ManeuverTurn, p.NextStep.Maneuver.Type)
+		assert.Empty(t, p.Events)
+
+		// Around the corner
+		p = tracker.Update(Fix{Location: base.Location{Latitude: 38.905, Longitude: -76.99}, Time: now.Add(time.Minute)})
+		assert.EqualValues(t, 1, p.StepIndex)
+		assert.EqualValues(t, []EventType{EventStepChanged}, p.Events)
+		assert.InDelta(t, 50, p.DurationRemaining.Seconds(), 1)
+
+		// At the destination
+		p = tracker.Update(Fix{Location: end, Time: now.Add(2 * time.Minute)})
+		assert.Contains(t, p.Events, EventArrived)
+		assert.InDelta(t, 0, p.DistanceRemaining, 1)
+		assert.Nil(t, p.NextStep)
+	})
+
+	t.Run("Uses leg annotations for duration", func(t *testing.T) {
+		route := testRoute()
+		route.Legs[0].Annotation = directions.Annotation{Distance: []float64{866, 1112}, Duration: []float64{150, 50}}
+
+		tracker, err := NewTracker(route, &TrackerOpts{Geometry: directions.GeometryPolyline6})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		p := tracker.Update(Fix{Location: base.Location{Latitude: 38.90, Longitude: -76.995}, Time: now})
+		assert.InDelta(t, 125, p.DurationRemaining.Seconds(), 1)
+	})
+
+	t.Run("Detects off-route after dwell", func(t *testing.T) {
+		tracker, err := NewTracker(testRoute(), &TrackerOpts{Geometry: directions.GeometryPolyline6, Tolerance: 30, Dwell: 10 * time.Second})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		tracker.Update(Fix{Location: start, Time: now})
+
+		away := base.Location{Latitude: 38.899, Longitude: -76.998}
+
+		// Inaccurate fixes are given the benefit of the doubt
+		p := tracker.Update(Fix{Location: away, Time: now.Add(time.Second), Accuracy: 100})
+		assert.InDelta(t, 111, p.DistanceFromRoute, 2)
+		assert.Empty(t, p.Events)
+
+		p = tracker.Update(Fix{Location: away, Time: now.Add(2 * time.Second)})
+		assert.Empty(t, p.Events)
+		assert.False(t, p.OffRoute)
+
+		p = tracker.Update(Fix{Location: away, Time: now.Add(12 * time.Second)})
+		assert.EqualValues(t, []EventType{EventOffRoute}, p.Events)
+		assert.True(t, p.OffRoute)
+
+		p = tracker.Update(Fix{Location: away, Time: now.Add(14 * time.Second)})
+		assert.Empty(t, p.Events)
+
+		p = tracker.Update(Fix{Location: base.Location{Latitude: 38.90, Longitude: -76.997}, Time: now.Add(20 * time.Second)})
+		assert.EqualValues(t, []EventType{EventRejoined}, p.Events)
+		assert.False(t, p.OffRoute)
+	})
+	t.Run("Treats invalid fixes as off-route", func(t *testing.T) {
+		tracker, err := NewTracker(testRoute(), &TrackerOpts{Geometry: directions.GeometryPolyline6, Tolerance: 30, Dwell: 10 * time.Second})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		tracker.Update(Fix{Location: start, Time: now})
+
+		invalid := base.Location{Latitude: math.NaN(), Longitude: math.NaN()}
+		p := tracker.Update(Fix{Location: invalid, Time: now.Add(time.Second)})
+		assert.Empty(t, p.Events)
+		assert.True(t, math.IsInf(p.DistanceFromRoute, 1))
+		assert.EqualValues(t, 0, p.DistanceTraveled)
+
+		p = tracker.Update(Fix{Location: invalid, Time: now.Add(12 * time.Second)})
+		assert.EqualValues(t, []EventType{EventOffRoute}, p.Events)
+		assert.True(t, p.OffRoute)
+	})
+}
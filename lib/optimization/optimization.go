@@ -0,0 +1,179 @@
+/**
+ * go-mapbox Optimization Module
+ * Wraps the mapbox optimization (v1) API for server side use
+ * See https://docs.mapbox.com/api/navigation/optimization-v1/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package optimization
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+const (
+	apiName    = "optimized-trips"
+	apiVersion = "v1"
+)
+
+const (
+	// MinCoordinates is the minimum number of coordinates in an optimization request
+	MinCoordinates = 2
+	// MaxCoordinates is the maximum number of coordinates in an optimization request
+	MaxCoordinates = 12
+)
+
+// RoutingProfile defines routing mode for trip optimization
+type RoutingProfile string
+
+const (
+	// RoutingDrivingTraffic mode for automotive routing takes into account current and historic traffic
+	RoutingDrivingTraffic RoutingProfile = "mapbox/driving-traffic"
+	// RoutingDriving mode for for automovide routing
+	RoutingDriving RoutingProfile = "mapbox/driving"
+	// RoutingWalking mode for Pedestrian routing
+	RoutingWalking RoutingProfile = "mapbox/walking"
+	// RoutingCycling mode for bicycle routing
+	RoutingCycling RoutingProfile = "mapbox/cycling"
+)
+
+// SourceType fixes the start of a trip
+type SourceType string
+
+const (
+	SourceAny   SourceType = "any"
+	SourceFirst SourceType = "first"
+)
+
+// DestinationType fixes the end of a trip
+type DestinationType string
+
+const (
+	DestinationAny  DestinationType = "any"
+	DestinationLast DestinationType = "last"
+)
+
+// Distribution is a pickup and dropoff pair of coordinate indices, the pickup is visited before the dropoff
+type Distribution struct {
+	Pickup, Dropoff int
+}
+
+// Optimization api wrapper instance
+type Optimization struct {
+	base *base.Base
+}
+
+// NewOptimization Create a new Optimization API wrapper
+func NewOptimization(base *base.Base) *Optimization {
+	return &Optimization{base}
+}
+
+// RequestOpts request options for optimization api
+type RequestOpts struct {
+	// Roundtrip returns to the first location, defaults to true when nil
+	Roundtrip   *bool                   `url:"roundtrip,omitempty"`
+	Source      SourceType              `url:"source,omitempty"`
+	Destination DestinationType         `url:"destination,omitempty"`
+	Geometries  directions.GeometryType `url:"geometries,omitempty"`
+	Overview    directions.OverviewType `url:"overview,omitempty"`
+	Steps       bool                    `url:"steps,omitempty"`
+	Annotations string                  `url:"annotations,omitempty"`
+	Language    string                  `url:"language,omitempty"`
+	Radiuses    string                  `url:"radiuses,omitempty"`
+	Bearings    string                  `url:"bearings,omitempty"`
+	Approaches  string                  `url:"approaches,omitempty"`
+	// Distributions are pickup and dropoff pairs, see SetDistributions
+	Distributions string `url:"distributions,omitempty"`
+}
+
+// SetRoundtrip sets whether the trip returns to the first location
+func (o *RequestOpts) SetRoundtrip(roundtrip bool) {
+	o.Roundtrip = &roundtrip
+}
+
+// SetAnnotations builds the annotations query argument from an array of annotation types
+func (o *RequestOpts) SetAnnotations(annotations []directions.AnnotationType) {
+	lines := make([]string, len(annotations))
+	for i, a := range annotations {
+		lines[i] = string(a)
+	}
+	o.Annotations = strings.Join(lines, ",")
+}
+
+// SetDistributions builds the distributions query argument from pickup and dropoff pairs
+func (o *RequestOpts) SetDistributions(distributions []Distribution) {
+	lines := make([]string, len(distributions))
+	for i, d := range distributions {
+		lines[i] = fmt.Sprintf("%d,%d", d.Pickup, d.Dropoff)
+	}
+	o.Distributions = strings.Join(lines, ";")
+}
+
+// Validate checks the number of coordinates and the trip constraints
+func (o *RequestOpts) Validate(coordinates int) error {
+	if coordinates < MinCoordinates || coordinates > MaxCoordinates {
+		return fmt.Errorf("Optimization request error: between %d and %d coordinates are required (received %d)", MinCoordinates, MaxCoordinates, coordinates)
+	}
+	if o == nil {
+		return nil
+	}
+
+	if o.Roundtrip != nil && !*o.Roundtrip && (o.Source != SourceFirst || o.Destination != DestinationLast) {
+		return fmt.Errorf("Optimization request error: trips that are not roundtrips require source first and destination last")
+	}
+
+	for _, field := range []struct{ name, value string }{{"radiuses", o.Radiuses}, {"bearings", o.Bearings}, {"approaches", o.Approaches}} {
+		if field.value != "" && len(strings.Split(field.value, ";")) != coordinates {
+			return fmt.Errorf("Optimization request error: %s requires %d elements", field.name, coordinates)
+		}
+	}
+
+	if o.Distributions != "" {
+		for _, d := range strings.Split(o.Distributions, ";") {
+			var pickup, dropoff int
+			if _, err := fmt.Sscanf(d, "%d,%d", &pickup, &dropoff); err != nil {
+				return fmt.Errorf("Optimization request error: invalid distribution '%s'", d)
+			}
+			if pickup < 0 || pickup >= coordinates || dropoff < 0 || dropoff >= coordinates || pickup == dropoff {
+				return fmt.Errorf("Optimization request error: invalid distribution '%s' for %d coordinates", d, coordinates)
+			}
+			if dropoff == 0 && o.Source == SourceFirst {
+				return fmt.Errorf("Optimization request error: distribution '%s' drops off at the fixed source", d)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetOptimizedTrip finds the optimal order to visit a set of locations using the specified routing profile
+func (g *Optimization) GetOptimizedTrip(locations []base.Location, profile RoutingProfile, opts *RequestOpts) (*OptimizationResponse, error) {
+
+	if err := opts.Validate(len(locations)); err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinateStrings := make([]string, len(locations))
+	for i, l := range locations {
+		coordinateStrings[i] = fmt.Sprintf("%f,%f", l.Longitude, l.Latitude)
+	}
+	queryString := strings.Join(coordinateStrings, ";")
+
+	resp := OptimizationResponse{}
+
+	err = g.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+
+	return &resp, err
+}
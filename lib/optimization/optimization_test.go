@@ -0,0 +1,94 @@
+/**
+ * go-mapbox Optimization Module Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package optimization
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+const tripResponse = `{
+	"code": "Ok",
+	"waypoints": [
+		{"name": "A", "location": [-122.42, 37.78], "waypoint_index": 0, "trips_index": 0},
+		{"name": "B", "location": [-122.45, 37.91], "waypoint_index": 2, "trips_index": 0},
+		{"name": "C", "location": [-122.48, 37.73], "waypoint_index": 1, "trips_index": 0}
+	],
+	"trips": [{"distance": 48000, "duration": 3600, "weight_name": "routability", "legs": [{}, {}, {}]}]
+}`
+
+func TestOptimization(t *testing.T) {
+
+	var lastPath string
+	var lastQuery map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastPath, lastQuery = r.URL.Path, r.URL.Query()
+		w.Write([]byte(tripResponse))
+	}))
+	defer server.Close()
+
+	b, err := base.NewBase("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetBaseURL(server.URL)
+	o := NewOptimization(b)
+
+	locs := []base.Location{
+		{Latitude: 37.78, Longitude: -122.42},
+		{Latitude: 37.91, Longitude: -122.45},
+		{Latitude: 37.73, Longitude: -122.48},
+	}
+
+	t.Run("Optimizes trips", func(t *testing.T) {
+		opts := RequestOpts{Source: SourceFirst, Destination: DestinationLast, Geometries: directions.GeometryPolyline6}
+		opts.SetRoundtrip(false)
+		opts.SetDistributions([]Distribution{{Pickup: 1, Dropoff: 2}})
+
+		res, err := o.GetOptimizedTrip(locs, RoutingDriving, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, "/optimized-trips/v1/mapbox/driving/-122.420000,37.780000;-122.450000,37.910000;-122.480000,37.730000", lastPath)
+		assert.EqualValues(t, []string{"false"}, lastQuery["roundtrip"])
+		assert.EqualValues(t, []string{"1,2"}, lastQuery["distributions"])
+
+		assert.EqualValues(t, CodeOK, res.Code)
+		assert.EqualValues(t, 2, res.Waypoints[1].WaypointIndex)
+		assert.EqualValues(t, []int{0, 2, 1}, res.Order(0))
+		if assert.Len(t, res.Trips, 1) {
+			assert.EqualValues(t, "routability", res.Trips[0].WeightName)
+			assert.Len(t, res.Trips[0].Legs, 3)
+		}
+	})
+
+	t.Run("Validates requests", func(t *testing.T) {
+		_, err := o.GetOptimizedTrip(locs[:1], RoutingDriving, nil)
+		assert.NotNil(t, err)
+
+		opts := RequestOpts{}
+		opts.SetRoundtrip(false)
+		assert.NotNil(t, opts.Validate(3))
+
+		opts = RequestOpts{}
+		opts.SetDistributions([]Distribution{{Pickup: 1, Dropoff: 5}})
+		assert.NotNil(t, opts.Validate(3))
+
+		opts = RequestOpts{Source: SourceFirst}
+		opts.SetDistributions([]Distribution{{Pickup: 1, Dropoff: 0}})
+		assert.NotNil(t, opts.Validate(3))
+	})
+}
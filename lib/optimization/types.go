@@ -0,0 +1,63 @@
+/**
+ * go-mapbox Optimization Module Types
+ * Wraps the mapbox optimization (v1) API for server side use
+ * See https://docs.mapbox.com/api/navigation/optimization-v1/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package optimization
+
+import (
+	"sort"
+
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+// OptimizationResponse is the response from GetOptimizedTrip
+// https://docs.mapbox.com/api/navigation/optimization-v1/#optimization-response-object
+type OptimizationResponse struct {
+	Code string
+	// Waypoints are in input order, with their position in the optimized trip
+	Waypoints []Waypoint
+	Trips     []directions.Route
+}
+
+// Waypoint is an input point snapped to the road network
+// https://docs.mapbox.com/api/navigation/optimization-v1/#waypoint-object
+type Waypoint struct {
+	Name     string
+	Location []float64
+	// WaypointIndex is the position of the waypoint in the trip
+	WaypointIndex int `json:"waypoint_index"`
+	// TripsIndex is the index of the trip containing the waypoint
+	TripsIndex int `json:"trips_index"`
+}
+
+// Order returns the input location indices in the order they are visited in a trip
+func (r *OptimizationResponse) Order(trip int) []int {
+	order := []int{}
+	for i, w := range r.Waypoints {
+		if w.TripsIndex == trip {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return r.Waypoints[order[a]].WaypointIndex < r.Waypoints[order[b]].WaypointIndex
+	})
+	return order
+}
+
+// Codes are optimization response Codes
+// https://docs.mapbox.com/api/navigation/optimization-v1/#optimization-errors
+type Codes string
+
+const (
+	CodeOK              Codes = "Ok"
+	CodeNoRoute         Codes = "NoRoute"
+	CodeNoTrips         Codes = "NoTrips"
+	CodeNotImplemented  Codes = "NotImplemented"
+	CodeProfileNotFound Codes = "ProfileNotFound"
+	CodeInvalidInput    Codes = "InvalidInput"
+)
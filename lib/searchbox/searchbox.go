@@ -0,0 +1,171 @@
+/**
+ * go-mapbox Search Box Module
+ * Wraps the mapbox search box API for server side use
+ * See https://docs.mapbox.com/api/search/search-box/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package searchbox
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "search/searchbox"
+	apiVersion = "v1"
+)
+
+// SearchBox api wrapper instance
+type SearchBox struct {
+	base *base.Base
+}
+
+// NewSearchBox Create a new Search Box API wrapper
+func NewSearchBox(base *base.Base) *SearchBox {
+	return &SearchBox{base}
+}
+
+// SuggestRequestOpts request options for type-ahead suggestions
+type SuggestRequestOpts struct {
+	Language              string            `url:"language,omitempty"`
+	Limit                 uint              `url:"limit,omitempty"`
+	Proximity             []float64         `url:"proximity,omitempty,comma"`
+	BBox                  base.BoundingBox  `url:"bbox,omitempty,comma"`
+	Country               string            `url:"country,omitempty"`
+	Types                 []Type            `url:"types,omitempty,comma"`
+	POICategory           []string          `url:"poi_category,omitempty,comma"`
+	POICategoryExclusions []string          `url:"poi_category_exclusions,omitempty,comma"`
+	NavigationProfile     NavigationProfile `url:"navigation_profile,omitempty"`
+	Origin                []float64         `url:"origin,omitempty,comma"`
+	ETAType               string            `url:"eta_type,omitempty"`
+}
+
+// RetrieveRequestOpts request options for retrieving a suggested feature
+type RetrieveRequestOpts struct {
+	Language          string            `url:"language,omitempty"`
+	NavigationProfile NavigationProfile `url:"navigation_profile,omitempty"`
+	Origin            []float64         `url:"origin,omitempty,comma"`
+	ETAType           string            `url:"eta_type,omitempty"`
+}
+
+// CategoryRequestOpts request options for category search
+type CategoryRequestOpts struct {
+	Language              string           `url:"language,omitempty"`
+	Limit                 uint             `url:"limit,omitempty"`
+	Proximity             []float64        `url:"proximity,omitempty,comma"`
+	BBox                  base.BoundingBox `url:"bbox,omitempty,comma"`
+	Country               string           `url:"country,omitempty"`
+	POICategoryExclusions []string         `url:"poi_category_exclusions,omitempty,comma"`
+}
+
+// ReverseRequestOpts request options for reverse lookups
+type ReverseRequestOpts struct {
+	Language string `url:"language,omitempty"`
+	Limit    uint   `url:"limit,omitempty"`
+	Country  string `url:"country,omitempty"`
+	Types    []Type `url:"types,omitempty,comma"`
+}
+
+// ListCategoriesRequestOpts request options for listing categories
+type ListCategoriesRequestOpts struct {
+	Language string `url:"language,omitempty"`
+}
+
+func (s *SearchBox) query(endpoint string, v *url.Values, inst interface{}) error {
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, endpoint)
+	return s.base.QueryBase(queryString, v, inst)
+}
+
+// Suggest fetches type-ahead suggestions for a partial query
+// All Suggest and Retrieve calls for a single search should share a session token, see Session
+func (s *SearchBox) Suggest(q, sessionToken string, opts *SuggestRequestOpts) (*SuggestResponse, error) {
+	if sessionToken == "" {
+		return nil, ErrorNoSessionToken
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.Set("q", q)
+	v.Set("session_token", sessionToken)
+
+	resp := SuggestResponse{}
+
+	err = s.query("suggest", &v, &resp)
+
+	return &resp, err
+}
+
+// Retrieve fetches the full feature for a suggestion returned by Suggest
+func (s *SearchBox) Retrieve(mapboxID, sessionToken string, opts *RetrieveRequestOpts) (*FeatureCollection, error) {
+	if sessionToken == "" {
+		return nil, ErrorNoSessionToken
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.Set("session_token", sessionToken)
+
+	resp := FeatureCollection{}
+
+	err = s.query(fmt.Sprintf("retrieve/%s", url.PathEscape(mapboxID)), &v, &resp)
+
+	return &resp, err
+}
+
+// Category finds points of interest matching a canonical category ID (see ListCategories)
+func (s *SearchBox) Category(categoryID string, opts *CategoryRequestOpts) (*FeatureCollection, error) {
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := FeatureCollection{}
+
+	err = s.query(fmt.Sprintf("category/%s", url.PathEscape(categoryID)), &v, &resp)
+
+	return &resp, err
+}
+
+// ListCategories fetches the categories supported by Category
+func (s *SearchBox) ListCategories(opts *ListCategoriesRequestOpts) (*CategoryListResponse, error) {
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := CategoryListResponse{}
+
+	err = s.query("list/category", &v, &resp)
+
+	return &resp, err
+}
+
+// Reverse finds features at a location
+func (s *SearchBox) Reverse(loc *base.Location, opts *ReverseRequestOpts) (*FeatureCollection, error) {
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.Set("longitude", fmt.Sprintf("%f", loc.Longitude))
+	v.Set("latitude", fmt.Sprintf("%f", loc.Latitude))
+
+	resp := FeatureCollection{}
+
+	err = s.query("reverse", &v, &resp)
+
+	return &resp, err
+}
@@ -0,0 +1,141 @@
+/**
+ * go-mapbox Search Box Module Tests
+ * Wraps the mapbox search box API for server side use
+ * See https://docs.mapbox.com/api/search/search-box/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package searchbox
+
+import (
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestSessions(t *testing.T) {
+
+	b, err := base.NewBase("fake-token")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := NewSearchBox(b).NewSession()
+	session.now = func() time.Time { return now }
+
+	t.Run("Generates UUIDv4 session tokens", func(t *testing.T) {
+		token, err := NewSessionToken()
+		assert.Nil(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), token)
+	})
+
+	t.Run("Reuses tokens within a session", func(t *testing.T) {
+		a, err := session.Token()
+		assert.Nil(t, err)
+
+		now = now.Add(SessionDuration / 2)
+
+		b, err := session.Token()
+		assert.Nil(t, err)
+		assert.EqualValues(t, a, b)
+	})
+
+	t.Run("Rotates tokens on expiry", func(t *testing.T) {
+		a, _ := session.Token()
+
+		now = now.Add(SessionDuration)
+
+		b, _ := session.Token()
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("Rotates tokens at the suggest limit", func(t *testing.T) {
+		a, _ := session.Token()
+
+		session.suggestions = SessionSuggestLimit
+
+		b, _ := session.Token()
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("Rotates tokens when ended", func(t *testing.T) {
+		a, _ := session.Token()
+
+		session.End()
+
+		b, _ := session.Token()
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("Requires session tokens", func(t *testing.T) {
+		_, err := NewSearchBox(b).Suggest("coffee", "", nil)
+		assert.EqualValues(t, ErrorNoSessionToken, err)
+	})
+}
+
+func TestSearchBox(t *testing.T) {
+
+	b, err := base.NewBase(os.Getenv("MAPBOX_TOKEN"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	searchBox := NewSearchBox(b)
+
+	t.Run("Can suggest and retrieve", func(t *testing.T) {
+		session := searchBox.NewSession()
+
+		var opts SuggestRequestOpts
+		opts.Limit = 1
+		opts.Proximity = []float64{-77.03, 38.91}
+
+		res, err := session.Suggest("lincoln memorial", &opts)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if len(res.Suggestions) == 0 {
+			t.Errorf("No suggestions returned")
+			t.FailNow()
+		}
+
+		features, err := session.Retrieve(res.Suggestions[0].MapboxID, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "FeatureCollection", features.Type)
+	})
+
+	t.Run("Can list and search categories", func(t *testing.T) {
+		list, err := searchBox.ListCategories(nil)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, list.ListItems)
+
+		var opts CategoryRequestOpts
+		opts.Limit = 1
+		opts.Proximity = []float64{-77.03, 38.91}
+
+		res, err := searchBox.Category("cafe", &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "FeatureCollection", res.Type)
+	})
+
+	t.Run("Can reverse lookup", func(t *testing.T) {
+		var opts ReverseRequestOpts
+		opts.Limit = 1
+
+		loc := &base.Location{Latitude: 38.889, Longitude: -77.050}
+
+		res, err := searchBox.Reverse(loc, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "FeatureCollection", res.Type)
+	})
+}
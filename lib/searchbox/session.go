@@ -0,0 +1,135 @@
+/**
+ * go-mapbox Search Box Module Sessions
+ * Manages session tokens used to group Suggest and Retrieve calls for billing
+ * See https://docs.mapbox.com/api/search/search-box/#session-based-pricing for information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package searchbox
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// SessionDuration is the maximum lifetime of a session before a new token is required
+	SessionDuration = 60 * time.Minute
+	// SessionSuggestLimit is the maximum number of Suggest calls billed to a single session
+	SessionSuggestLimit = 50
+)
+
+// ErrorNoSessionToken indicates a Suggest or Retrieve call was made without a session token
+var ErrorNoSessionToken = errors.New("Search Box API error session token required")
+
+// NewSessionToken generates a new random (UUIDv4) session token
+func NewSessionToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// Set version (4) and variant (RFC4122) bits
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// Session groups a series of Suggest calls and the final Retrieve call under one session token
+// Tokens are rotated automatically after a Retrieve, when the session expires, or when
+// the suggest limit is reached. Sessions are safe for concurrent use.
+type Session struct {
+	sb *SearchBox
+
+	mu          sync.Mutex
+	token       string
+	started     time.Time
+	suggestions int
+
+	now func() time.Time
+}
+
+// NewSession creates a new search session
+func (s *SearchBox) NewSession() *Session {
+	return &Session{sb: s, now: time.Now}
+}
+
+// Token fetches the current session token, starting a new session if required
+func (s *Session) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.current()
+}
+
+// End ends the current session, the next call will use a new token
+func (s *Session) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+}
+
+// current fetches or rotates the session token, s.mu must be held
+func (s *Session) current() (string, error) {
+	now := s.now()
+
+	if s.token != "" && now.Sub(s.started) < SessionDuration && s.suggestions < SessionSuggestLimit {
+		return s.token, nil
+	}
+
+	token, err := NewSessionToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.started = now
+	s.suggestions = 0
+
+	return s.token, nil
+}
+
+// Suggest fetches type-ahead suggestions within the session
+func (s *Session) Suggest(q string, opts *SuggestRequestOpts) (*SuggestResponse, error) {
+	s.mu.Lock()
+	token, err := s.current()
+	if err == nil {
+		s.suggestions++
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sb.Suggest(q, token, opts)
+}
+
+// Retrieve fetches the full feature for a suggestion and ends the session
+func (s *Session) Retrieve(mapboxID string, opts *RetrieveRequestOpts) (*FeatureCollection, error) {
+	token, err := s.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.sb.Retrieve(mapboxID, token, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only end the session for the token used, in case another call has already rotated it
+	s.mu.Lock()
+	if s.token == token {
+		s.token = ""
+	}
+	s.mu.Unlock()
+
+	return resp, nil
+}
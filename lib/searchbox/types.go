@@ -0,0 +1,200 @@
+/**
+ * go-mapbox Search Box Module Types
+ * Wraps the mapbox search box API for server side use
+ * See https://docs.mapbox.com/api/search/search-box/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package searchbox
+
+// Type defines search box feature types
+type Type string
+
+const (
+	// Country level
+	Country Type = "country"
+	// Region level
+	Region Type = "region"
+	// Postcode level
+	Postcode Type = "postcode"
+	// District level
+	District Type = "district"
+	// Place level
+	Place Type = "place"
+	// City level
+	City Type = "city"
+	// Locality level
+	Locality Type = "locality"
+	// Neighborhood level
+	Neighborhood Type = "neighborhood"
+	// Street level
+	Street Type = "street"
+	// Address level
+	Address Type = "address"
+	// POI (Point of Interest) level
+	POI Type = "poi"
+	// Category suggestion (only returned by Suggest)
+	Category Type = "category"
+	// Brand suggestion (only returned by Suggest)
+	Brand Type = "brand"
+)
+
+// NavigationProfile selects the routing profile used for ETA calculations
+type NavigationProfile string
+
+const (
+	// NavigationDriving for automotive ETAs
+	NavigationDriving NavigationProfile = "driving"
+	// NavigationWalking for pedestrian ETAs
+	NavigationWalking NavigationProfile = "walking"
+	// NavigationCycling for bicycle ETAs
+	NavigationCycling NavigationProfile = "cycling"
+)
+
+// ContextCountry is the country a result belongs to
+type ContextCountry struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	CountryCode       string `json:"country_code"`
+	CountryCodeAlpha3 string `json:"country_code_alpha_3"`
+}
+
+// ContextRegion is the region a result belongs to
+type ContextRegion struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	RegionCode     string `json:"region_code"`
+	RegionCodeFull string `json:"region_code_full"`
+}
+
+// ContextAddress is the address component of a result
+type ContextAddress struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	AddressNumber string `json:"address_number"`
+	StreetName    string `json:"street_name"`
+}
+
+// ContextItem is a generic named level of the result hierarchy
+type ContextItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Context describes the hierarchy of places containing a result
+// Levels that do not apply to a result are nil
+type Context struct {
+	Country      *ContextCountry `json:"country,omitempty"`
+	Region       *ContextRegion  `json:"region,omitempty"`
+	Postcode     *ContextItem    `json:"postcode,omitempty"`
+	District     *ContextItem    `json:"district,omitempty"`
+	Place        *ContextItem    `json:"place,omitempty"`
+	Locality     *ContextItem    `json:"locality,omitempty"`
+	Neighborhood *ContextItem    `json:"neighborhood,omitempty"`
+	Address      *ContextAddress `json:"address,omitempty"`
+	Street       *ContextItem    `json:"street,omitempty"`
+}
+
+// Suggestion is a single type-ahead result returned by Suggest
+// Suggestions carry no coordinates, use Retrieve with the MapboxID to fetch the full feature
+type Suggestion struct {
+	Name              string            `json:"name"`
+	NamePreferred     string            `json:"name_preferred"`
+	MapboxID          string            `json:"mapbox_id"`
+	FeatureType       Type              `json:"feature_type"`
+	Address           string            `json:"address"`
+	FullAddress       string            `json:"full_address"`
+	PlaceFormatted    string            `json:"place_formatted"`
+	Context           Context           `json:"context"`
+	Language          string            `json:"language"`
+	Maki              string            `json:"maki"`
+	POICategory       []string          `json:"poi_category"`
+	POICategoryIDs    []string          `json:"poi_category_ids"`
+	Brand             []string          `json:"brand"`
+	BrandID           []string          `json:"brand_id"`
+	ExternalIDs       map[string]string `json:"external_ids"`
+	Distance          float64           `json:"distance"`
+	ETA               float64           `json:"eta"`
+	OperationalStatus string            `json:"operational_status"`
+}
+
+// SuggestResponse is the response from a Suggest request
+type SuggestResponse struct {
+	Suggestions []Suggestion `json:"suggestions"`
+	Attribution string       `json:"attribution"`
+}
+
+// RoutablePoint is an access point to a feature, such as a building entrance
+type RoutablePoint struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Coordinates is the detailed location of a retrieved feature
+type Coordinates struct {
+	Latitude       float64         `json:"latitude"`
+	Longitude      float64         `json:"longitude"`
+	Accuracy       string          `json:"accuracy"`
+	RoutablePoints []RoutablePoint `json:"routable_points"`
+}
+
+// Properties are the properties of a retrieved feature
+type Properties struct {
+	Name           string                 `json:"name"`
+	NamePreferred  string                 `json:"name_preferred"`
+	MapboxID       string                 `json:"mapbox_id"`
+	FeatureType    Type                   `json:"feature_type"`
+	Address        string                 `json:"address"`
+	FullAddress    string                 `json:"full_address"`
+	PlaceFormatted string                 `json:"place_formatted"`
+	Context        Context                `json:"context"`
+	Coordinates    Coordinates            `json:"coordinates"`
+	BBox           []float64              `json:"bbox"`
+	Language       string                 `json:"language"`
+	Maki           string                 `json:"maki"`
+	POICategory    []string               `json:"poi_category"`
+	POICategoryIDs []string               `json:"poi_category_ids"`
+	Brand          []string               `json:"brand"`
+	BrandID        []string               `json:"brand_id"`
+	ExternalIDs    map[string]string      `json:"external_ids"`
+	Metadata       map[string]interface{} `json:"metadata"`
+}
+
+// Geometry is a GeoJSON point geometry
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// Feature is a GeoJSON feature returned by Retrieve, Category and Reverse
+type Feature struct {
+	Type       string     `json:"type"`
+	Geometry   Geometry   `json:"geometry"`
+	Properties Properties `json:"properties"`
+}
+
+// FeatureCollection is a GeoJSON feature collection returned by Retrieve, Category and Reverse
+type FeatureCollection struct {
+	Type        string    `json:"type"`
+	Features    []Feature `json:"features"`
+	Attribution string    `json:"attribution"`
+}
+
+// CategoryItem is an entry in the list of supported categories
+type CategoryItem struct {
+	CanonicalID string `json:"canonical_id"`
+	Icon        string `json:"icon"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	UUID        string `json:"uuid"`
+}
+
+// CategoryListResponse is the response from a ListCategories request
+type CategoryListResponse struct {
+	ListItems   []CategoryItem `json:"list_items"`
+	Attribution string         `json:"attribution"`
+	Version     string         `json:"version"`
+}
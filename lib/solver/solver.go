@@ -0,0 +1,416 @@
+/**
+ * go-mapbox Tour Solver Module
+ * Solves small travelling salesman and vehicle routing problems locally from a cost matrix,
+ * as an alternative to the optimization API
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package solver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+const (
+	// DefaultIterations is the default maximum number of improvement moves
+	DefaultIterations = 1000
+	// orOptSegment is the maximum number of consecutive stops moved by or-opt
+	orOptSegment = 3
+	// epsilon is the minimum cost reduction for a move to be an improvement
+	epsilon = 1e-9
+)
+
+// Stop describes constraints on visiting a location
+type Stop struct {
+	// Demand is the vehicle capacity used by the stop
+	Demand float64
+	// Service is the time in seconds spent at the stop
+	Service float64
+	// Earliest and Latest are the arrival time window in seconds from departure, vehicles arriving early wait
+	// until Earliest, and a Latest of zero is unconstrained
+	Earliest, Latest float64
+}
+
+// Problem is a set of locations to visit
+type Problem struct {
+	// Costs is the square matrix of travel costs between locations (eg. durations or distances from
+	// GetDirectionsMatrix), where infinite costs (directionsmatrix.Unreachable) are not traveled
+	Costs [][]float64
+	// Durations is the square matrix of travel times in seconds for time windows, Costs are used if nil
+	Durations [][]float64
+	// Stops are the constraints for each location, unconstrained if nil
+	Stops []Stop
+	// Start is the location each tour departs from
+	Start int
+	// End is the location each tour finishes at, which may be the start for round trips or -1 to finish at the last stop
+	End int
+	// Vehicles is the number of tours available, one if not set
+	Vehicles int
+	// Capacity is the capacity of each vehicle, unlimited if not set
+	Capacity float64
+	// Iterations is the maximum number of improvement moves
+	Iterations int
+}
+
+// Tour is the route of a single vehicle
+type Tour struct {
+	// Locations are the location indices in visiting order, including the start and end
+	Locations []int
+	// Cost is the total travel cost
+	Cost float64
+	// Arrivals are the arrival times in seconds from departure at each location (after any waiting)
+	Arrivals []float64
+	// Load is the total demand of the stops
+	Load float64
+}
+
+// Solution is a set of tours visiting the problem locations
+type Solution struct {
+	Tours []Tour
+	// Unassigned are the locations that could not be visited within the constraints
+	Unassigned []int
+	// Cost is the total travel cost of all tours
+	Cost float64
+}
+
+type solver struct {
+	p         Problem
+	durations [][]float64
+}
+
+// Solve computes tours visiting every location other than the start and end, using nearest neighbour
+// construction followed by 2-opt and or-opt improvement
+func Solve(problem *Problem) (*Solution, error) {
+	p := *problem
+	n := len(p.Costs)
+	if p.Vehicles <= 0 {
+		p.Vehicles = 1
+	}
+	if p.Iterations <= 0 {
+		p.Iterations = DefaultIterations
+	}
+
+	if err := checkSquare("costs", p.Costs, n); err != nil {
+		return nil, err
+	}
+	if p.Durations != nil {
+		if err := checkSquare("durations", p.Durations, n); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case n == 0:
+		return nil, fmt.Errorf("Solver error: no locations")
+	case p.Stops != nil && len(p.Stops) != n:
+		return nil, fmt.Errorf("Solver error: %d stops provided for %d locations", len(p.Stops), n)
+	case p.Start < 0 || p.Start >= n:
+		return nil, fmt.Errorf("Solver error: start %d out of range", p.Start)
+	case p.End < -1 || p.End >= n:
+		return nil, fmt.Errorf("Solver error: end %d out of range", p.End)
+	case p.Capacity < 0:
+		return nil, fmt.Errorf("Solver error: capacity must not be negative")
+	}
+
+	s := &solver{p: p, durations: p.Durations}
+	if s.durations == nil {
+		s.durations = p.Costs
+	}
+
+	routes, unassigned := s.construct()
+	for i := 0; i < p.Iterations; i++ {
+		improved := false
+		if routes, unassigned, improved = s.insert(routes, unassigned); improved {
+			continue
+		}
+		if improved = s.twoOpt(routes); improved {
+			continue
+		}
+		if routes, improved = s.orOpt(routes); !improved {
+			break
+		}
+	}
+
+	solution := &Solution{Unassigned: unassigned}
+	for _, r := range routes {
+		cost, arrivals, load, _ := s.evaluate(r)
+		solution.Tours = append(solution.Tours, Tour{Locations: r, Cost: cost, Arrivals: arrivals, Load: load})
+		solution.Cost += cost
+	}
+
+	return solution, nil
+}
+
+func checkSquare(name string, m [][]float64, n int) error {
+	for _, row := range m {
+		if len(row) != n {
+			return fmt.Errorf("Solver error: %s must be a square matrix", name)
+		}
+	}
+	return nil
+}
+
+func (s *solver) stop(i int) Stop {
+	if s.p.Stops == nil {
+		return Stop{}
+	}
+	return s.p.Stops[i]
+}
+
+// route builds a route from the start through the stops to the end
+func (s *solver) route(stops []int) []int {
+	r := append([]int{s.p.Start}, stops...)
+	if s.p.End >= 0 {
+		r = append(r, s.p.End)
+	}
+	return r
+}
+
+// stops returns the stops of a route, excluding the start and end
+func (s *solver) stops(r []int) []int {
+	if s.p.End >= 0 {
+		return r[1 : len(r)-1]
+	}
+	return r[1:]
+}
+
+// evaluate calculates the cost, arrival times and load of a route, and whether it is feasible
+func (s *solver) evaluate(r []int) (float64, []float64, float64, bool) {
+	cost, t, load := 0.0, 0.0, 0.0
+	arrivals := make([]float64, len(r))
+
+	for k := 1; k < len(r); k++ {
+		a, b := r[k-1], r[k]
+		if math.IsInf(s.p.Costs[a][b], 1) || math.IsInf(s.durations[a][b], 1) {
+			return math.Inf(1), nil, 0, false
+		}
+		cost += s.p.Costs[a][b]
+
+		t += s.stop(a).Service + s.durations[a][b]
+		stop := s.stop(b)
+		if t < stop.Earliest {
+			t = stop.Earliest
+		}
+		if stop.Latest > 0 && t > stop.Latest {
+			return math.Inf(1), nil, 0, false
+		}
+		arrivals[k] = t
+
+		if b != s.p.Start && b != s.p.End {
+			load += stop.Demand
+		}
+	}
+
+	if s.p.Capacity > 0 && load > s.p.Capacity {
+		return math.Inf(1), nil, 0, false
+	}
+
+	return cost, arrivals, load, true
+}
+
+func (s *solver) cost(r []int) float64 {
+	cost, _, _, _ := s.evaluate(r)
+	return cost
+}
+
+// construct builds tours by repeatedly visiting the nearest feasible stop
+func (s *solver) construct() ([][]int, []int) {
+	unvisited := []int{}
+	for i := range s.p.Costs {
+		if i != s.p.Start && i != s.p.End {
+			unvisited = append(unvisited, i)
+		}
+	}
+
+	routes := [][]int{}
+	for v := 0; v < s.p.Vehicles && len(unvisited) > 0; v++ {
+		stops := []int{}
+		for {
+			last := s.p.Start
+			if len(stops) > 0 {
+				last = stops[len(stops)-1]
+			}
+
+			best, bestCost := -1, math.Inf(1)
+			for i, u := range unvisited {
+				if c := s.p.Costs[last][u]; c < bestCost {
+					if _, _, _, ok := s.evaluate(s.route(append(append([]int{}, stops...), u))); ok {
+						best, bestCost = i, c
+					}
+				}
+			}
+			if best < 0 {
+				break
+			}
+
+			stops = append(stops, unvisited[best])
+			unvisited = append(unvisited[:best], unvisited[best+1:]...)
+		}
+
+		if len(stops) > 0 {
+			routes = append(routes, s.route(stops))
+		}
+	}
+
+	return routes, unvisited
+}
+
+// insert inserts an unassigned stop at its cheapest feasible position, in an existing tour or a new tour
+func (s *solver) insert(routes [][]int, unassigned []int) ([][]int, []int, bool) {
+	for i, u := range unassigned {
+		bestRoute, bestPos, bestDelta := -1, 0, math.Inf(1)
+
+		for ri, r := range routes {
+			current := s.cost(r)
+			stops := s.stops(r)
+			for pos := 0; pos <= len(stops); pos++ {
+				candidate := s.route(insertAt(stops, pos, []int{u}))
+				if cost, _, _, ok := s.evaluate(candidate); ok && cost-current < bestDelta {
+					bestRoute, bestPos, bestDelta = ri, pos, cost-current
+				}
+			}
+		}
+		if len(routes) < s.p.Vehicles {
+			if cost, _, _, ok := s.evaluate(s.route([]int{u})); ok && cost < bestDelta {
+				bestRoute, bestDelta = len(routes), cost
+			}
+		}
+
+		if bestRoute < 0 {
+			continue
+		}
+		if bestRoute == len(routes) {
+			routes = append(routes, s.route([]int{u}))
+		} else {
+			routes[bestRoute] = s.route(insertAt(s.stops(routes[bestRoute]), bestPos, []int{u}))
+		}
+		unassigned = append(unassigned[:i:i], unassigned[i+1:]...)
+		return routes, unassigned, true
+	}
+
+	return routes, unassigned, false
+}
+
+// twoOpt applies the first improving reversal of a section of a tour
+func (s *solver) twoOpt(routes [][]int) bool {
+	for ri, r := range routes {
+		current := s.cost(r)
+		stops := s.stops(r)
+		for i := 0; i < len(stops)-1; i++ {
+			for j := i + 1; j < len(stops); j++ {
+				candidate := append([]int{}, stops...)
+				reverse(candidate[i : j+1])
+				candidate = s.route(candidate)
+				if cost, _, _, ok := s.evaluate(candidate); ok && cost < current-epsilon {
+					routes[ri] = candidate
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// orOpt applies the first improving move of up to orOptSegment consecutive stops (in either direction)
+// to another position in the same or another tour, removing tours left empty
+func (s *solver) orOpt(routes [][]int) ([][]int, bool) {
+	for ra := range routes {
+		fromStops := s.stops(routes[ra])
+		fromCost := s.cost(routes[ra])
+
+		for length := 1; length <= orOptSegment; length++ {
+			for i := 0; i+length <= len(fromStops); i++ {
+				segment := fromStops[i : i+length]
+				remaining := append(append([]int{}, fromStops[:i]...), fromStops[i+length:]...)
+
+				newFrom := s.route(remaining)
+				newFromCost, _, _, ok := s.evaluate(newFrom)
+				if !ok {
+					continue
+				}
+
+				for rb := range routes {
+					toStops, toCost := remaining, 0.0
+					if rb != ra {
+						toStops, toCost = s.stops(routes[rb]), s.cost(routes[rb])
+					}
+
+					for pos := 0; pos <= len(toStops); pos++ {
+						for _, reversed := range []bool{false, true} {
+							if (reversed && length == 1) || (!reversed && rb == ra && pos == i) {
+								continue
+							}
+							seg := append([]int{}, segment...)
+							if reversed {
+								reverse(seg)
+							}
+
+							candidate := s.route(insertAt(toStops, pos, seg))
+							cost, _, _, ok := s.evaluate(candidate)
+							if !ok {
+								continue
+							}
+
+							var delta float64
+							if rb == ra {
+								delta = cost - fromCost
+							} else {
+								delta = newFromCost + cost - fromCost - toCost
+							}
+							if delta >= -epsilon {
+								continue
+							}
+
+							routes[rb] = candidate
+							if rb != ra {
+								routes[ra] = newFrom
+								if len(remaining) == 0 {
+									routes = append(routes[:ra], routes[ra+1:]...)
+								}
+							}
+							return routes, true
+						}
+					}
+				}
+			}
+		}
+	}
+	return routes, false
+}
+
+func insertAt(stops []int, pos int, segment []int) []int {
+	out := make([]int, 0, len(stops)+len(segment))
+	out = append(out, stops[:pos]...)
+	out = append(out, segment...)
+	return append(out, stops[pos:]...)
+}
+
+func reverse(values []int) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
+// Path returns the locations of a tour in visiting order
+func (t *Tour) Path(locations []base.Location) []base.Location {
+	path := make([]base.Location, len(t.Locations))
+	for i, l := range t.Locations {
+		path[i] = locations[l]
+	}
+	return path
+}
+
+// GetGeometry fetches directions following a tour, split across multiple requests where the tour
+// has more locations than the profile allows in a single request (see directions.GetDirectionsSplit)
+func GetGeometry(d *directions.Directions, locations []base.Location, tour *Tour, profile directions.RoutingProfile, opts *directions.RequestOpts) (*directions.DirectionResponse, error) {
+	path := tour.Path(locations)
+	if len(path) > directions.MaxCoordinatesFor(profile) {
+		return d.GetDirectionsSplit(path, profile, opts, nil)
+	}
+	return d.GetDirections(path, profile, opts)
+}
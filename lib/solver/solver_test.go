@@ -0,0 +1,156 @@
+/**
+ * go-mapbox Tour Solver Module Tests
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package solver
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+	"github.com/ryankurte/go-mapbox/lib/directions_matrix"
+)
+
+// euclidean builds a cost matrix from the straight line distance between points
+func euclidean(points [][2]float64) [][]float64 {
+	costs := make([][]float64, len(points))
+	for i, a := range points {
+		costs[i] = make([]float64, len(points))
+		for j, b := range points {
+			costs[i][j] = math.Hypot(a[0]-b[0], a[1]-b[1])
+		}
+	}
+	return costs
+}
+
+func TestSolver(t *testing.T) {
+
+	t.Run("Solves round trips", func(t *testing.T) {
+		// Points around a circle in scrambled order
+		order := []int{0, 5, 2, 7, 4, 1, 9, 3, 8, 6}
+		points := make([][2]float64, len(order))
+		for i, o := range order {
+			angle := 2 * math.Pi * float64(o) / float64(len(order))
+			points[i] = [2]float64{math.Cos(angle), math.Sin(angle)}
+		}
+		side := math.Hypot(1-math.Cos(2*math.Pi/10), math.Sin(2*math.Pi/10))
+
+		s, err := Solve(&Problem{Costs: euclidean(points), Start: 0, End: 0})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Empty(t, s.Unassigned)
+		if assert.Len(t, s.Tours, 1) {
+			tour := s.Tours[0]
+			assert.Len(t, tour.Locations, 11)
+			assert.Equal(t, 0, tour.Locations[0])
+			assert.Equal(t, 0, tour.Locations[10])
+			assert.InDelta(t, 10*side, tour.Cost, 1e-6)
+		}
+		assert.InDelta(t, 10*side, s.Cost, 1e-6)
+	})
+
+	t.Run("Supports fixed and open ends", func(t *testing.T) {
+		points := [][2]float64{{0, 0}, {3, 0}, {1, 0}, {4, 0}, {2, 0}}
+
+		s, err := Solve(&Problem{Costs: euclidean(points), Start: 0, End: 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0, 2, 4, 1, 3}, s.Tours[0].Locations)
+
+		s, err = Solve(&Problem{Costs: euclidean(points), Start: 4, End: -1})
+		assert.Nil(t, err)
+		assert.Len(t, s.Tours[0].Locations, 5)
+		assert.InDelta(t, 6, s.Cost, 1e-9)
+	})
+
+	t.Run("Respects time windows", func(t *testing.T) {
+		points := [][2]float64{{0, 0}, {1, 0}, {2, 0}, {3, 0}}
+		stops := []Stop{{}, {Earliest: 10}, {Latest: 2}, {Service: 5}}
+
+		s, err := Solve(&Problem{Costs: euclidean(points), Stops: stops, Start: 0, End: 0})
+		assert.Nil(t, err)
+		tour := s.Tours[0]
+		assert.Equal(t, []int{0, 2, 3, 1, 0}, tour.Locations)
+		assert.Equal(t, []float64{0, 2, 3, 10, 11}, tour.Arrivals)
+	})
+
+	t.Run("Splits stops between vehicles by capacity", func(t *testing.T) {
+		points := [][2]float64{{0, 0}, {-1, 0}, {-2, 0}, {1, 0}, {2, 0}}
+		stops := []Stop{{}, {Demand: 1}, {Demand: 1}, {Demand: 1}, {Demand: 1}}
+
+		s, err := Solve(&Problem{Costs: euclidean(points), Stops: stops, Start: 0, End: 0, Vehicles: 2, Capacity: 2})
+		assert.Nil(t, err)
+		assert.Empty(t, s.Unassigned)
+		if assert.Len(t, s.Tours, 2) {
+			for _, tour := range s.Tours {
+				assert.Equal(t, 2.0, tour.Load)
+				assert.InDelta(t, 4, tour.Cost, 1e-9)
+			}
+		}
+
+		s, err = Solve(&Problem{Costs: euclidean(points), Stops: stops, Start: 0, End: 0, Capacity: 3})
+		assert.Nil(t, err)
+		assert.Len(t, s.Unassigned, 1)
+	})
+
+	t.Run("Leaves unreachable locations unassigned", func(t *testing.T) {
+		costs := euclidean([][2]float64{{0, 0}, {1, 0}, {2, 0}})
+		costs[0][2], costs[1][2] = directionsmatrix.Unreachable, directionsmatrix.Unreachable
+
+		s, err := Solve(&Problem{Costs: directionsmatrix.Matrix(costs), Start: 0, End: 0})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2}, s.Unassigned)
+		assert.Equal(t, []int{0, 1, 0}, s.Tours[0].Locations)
+	})
+
+	t.Run("Validates problems", func(t *testing.T) {
+		costs := euclidean([][2]float64{{0, 0}, {1, 0}})
+		cases := []Problem{
+			{},
+			{Costs: [][]float64{{0, 1}}},
+			{Costs: costs, Start: 2},
+			{Costs: costs, End: -2},
+			{Costs: costs, Stops: []Stop{{}}},
+			{Costs: costs, Durations: [][]float64{{0}}},
+			{Costs: costs, Capacity: -1},
+		}
+		for _, c := range cases {
+			_, err := Solve(&c)
+			assert.NotNil(t, err, "%+v", c)
+		}
+	})
+
+	t.Run("Fetches tour geometry", func(t *testing.T) {
+		requested := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested <- path.Base(r.URL.Path)
+			fmt.Fprintf(w, `{"code": "Ok", "routes": [{"distance": 1000, "duration": 100}]}`)
+		}))
+		defer server.Close()
+
+		b, _ := base.NewBase("test-token")
+		b.SetBaseURL(server.URL)
+
+		locations := []base.Location{{Latitude: 1, Longitude: 2}, {Latitude: 3, Longitude: 4}, {Latitude: 5, Longitude: 6}}
+		tour := &Tour{Locations: []int{0, 2, 1, 0}}
+
+		resp, err := GetGeometry(directions.NewDirections(b), locations, tour, directions.RoutingDriving, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "Ok", resp.Code)
+
+		coords := strings.Split(<-requested, ";")
+		assert.Equal(t, []string{"2.000000,1.000000", "6.000000,5.000000", "4.000000,3.000000", "2.000000,1.000000"}, coords)
+	})
+}